@@ -0,0 +1,171 @@
+package canonical
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const maxRedirectDepth = 5
+
+type Rule struct {
+	Domain string `json:"domain"`
+	// StripParams lists query parameters to remove. A name ending with "*" is a prefix, e.g. "utm_*".
+	StripParams       []string   `json:"strip_params"`
+	StripSubdomains   []string   `json:"strip_subdomains"`
+	KeepTrailingSlash bool       `json:"keep_trailing_slash"`
+	Redirects         []Redirect `json:"redirects"`
+}
+
+type Redirect struct {
+	PathPrefix string `json:"path_prefix"`
+	Param      string `json:"param"`
+}
+
+type Canonicalizer struct {
+	rules []Rule
+}
+
+// mobileDomains serve the same pages on the "m" and "mobile" subdomains, elsewhere these may be other sites.
+var mobileDomains = []string{"habr.com", "vc.ru", "youtube.com", "twitter.com", "x.com", "facebook.com", "reddit.com"}
+
+func DefaultRules() []Rule {
+	rules := []Rule{
+		{
+			StripParams: []string{"utm_*", "fbclid", "gclid", "yclid", "mc_cid", "mc_eid", "_hsenc", "_hsmi"},
+		},
+		{
+			Domain:      "medium.com",
+			StripParams: []string{"source", "sk"},
+			Redirects: []Redirect{
+				{PathPrefix: "/m/global-identity", Param: "redirectUrl"},
+				{PathPrefix: "/r/", Param: "url"},
+			},
+		},
+	}
+	for _, domain := range mobileDomains {
+		rules = append(rules, Rule{Domain: domain, StripSubdomains: []string{"m", "mobile"}})
+	}
+	return rules
+}
+
+func LoadRules(path string) ([]Rule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read URL rules: %w", err)
+	}
+
+	var rules []Rule
+	if err = json.Unmarshal(content, &rules); err != nil {
+		return nil, fmt.Errorf("can't parse URL rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func NewCanonicalizer(rules []Rule) *Canonicalizer {
+	return &Canonicalizer{
+		rules: rules,
+	}
+}
+
+func (c *Canonicalizer) Canonicalize(rawUrl string) (string, error) {
+	return c.canonicalize(rawUrl, 0)
+}
+
+func (c *Canonicalizer) canonicalize(rawUrl string, depth int) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawUrl))
+	if err != nil {
+		return "", fmt.Errorf("can't canonicalize URL: %w", err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("can't canonicalize URL without host: %q", rawUrl)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	if (u.Scheme == "https" && u.Port() == "443") || (u.Scheme == "http" && u.Port() == "80") {
+		u.Host = u.Hostname()
+	}
+
+	rules := c.matchingRules(u.Hostname())
+	query := u.Query()
+
+	if depth < maxRedirectDepth {
+		for _, rule := range rules {
+			for _, redirect := range rule.Redirects {
+				target := query.Get(redirect.Param)
+				if strings.HasPrefix(u.Path, redirect.PathPrefix) && target != "" {
+					return c.canonicalize(target, depth+1)
+				}
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		u.Host = stripSubdomains(u.Host, rule.StripSubdomains)
+		for name := range query {
+			if matchesAny(name, rule.StripParams) {
+				query.Del(name)
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	if !keepTrailingSlash(rules) {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+	}
+
+	return u.String(), nil
+}
+
+func (c *Canonicalizer) matchingRules(host string) []Rule {
+	var rules []Rule
+	for _, rule := range c.rules {
+		domain := strings.ToLower(rule.Domain)
+		if domain == "" || host == domain || strings.HasSuffix(host, "."+domain) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func stripSubdomains(host string, subdomains []string) string {
+	for _, subdomain := range subdomains {
+		prefix := strings.ToLower(subdomain) + "."
+		// Keep at least a second-level domain, so "m.com" stays as is.
+		if strings.HasPrefix(host, prefix) && strings.Contains(host[len(prefix):], ".") {
+			return host[len(prefix):]
+		}
+	}
+	return host
+}
+
+func matchesAny(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func keepTrailingSlash(rules []Rule) bool {
+	for _, rule := range rules {
+		if rule.KeepTrailingSlash {
+			return true
+		}
+	}
+	return false
+}
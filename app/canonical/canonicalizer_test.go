@@ -0,0 +1,96 @@
+package canonical
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize_DefaultRules(t *testing.T) {
+	canonicalizer := NewCanonicalizer(DefaultRules())
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"unchanged", "https://example.com/article", "https://example.com/article"},
+		{"tracking parameters", "https://example.com/article?utm_source=telegram&utm_medium=social&fbclid=abc&id=42", "https://example.com/article?id=42"},
+		{"mobile subdomain", "https://m.habr.com/ru/articles/123/", "https://habr.com/ru/articles/123"},
+		{"mobile subdomain of unknown site", "https://m.example.com/article", "https://m.example.com/article"},
+		{"trailing slash", "https://example.com/article/", "https://example.com/article"},
+		{"fragment and case", "HTTPS://Example.COM/article#comments", "https://example.com/article"},
+		{"default port", "https://example.com:443/article", "https://example.com/article"},
+		{"medium global identity redirect", "https://medium.com/m/global-identity?redirectUrl=https%3A%2F%2Fblog.example.com%2Fpost-123%3Fsource%3Drss", "https://blog.example.com/post-123?source=rss"},
+		{"medium source parameter", "https://engineering.medium.com/post-123?source=rss----abc", "https://engineering.medium.com/post-123"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual, err := canonicalizer.Canonicalize(tc.input)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestCanonicalize_DomainRules(t *testing.T) {
+	// Arrange
+	rules := append(DefaultRules(), Rule{Domain: "example.org", StripParams: []string{"ref"}, KeepTrailingSlash: true})
+	canonicalizer := NewCanonicalizer(rules)
+
+	// Act
+	matching, matchingErr := canonicalizer.Canonicalize("https://blog.example.org/article/?ref=hn")
+	other, otherErr := canonicalizer.Canonicalize("https://example.com/article/?ref=hn")
+
+	// Assert
+	assert.NoError(t, matchingErr)
+	assert.Equal(t, "https://blog.example.org/article/", matching)
+	assert.NoError(t, otherErr)
+	assert.Equal(t, "https://example.com/article?ref=hn", other)
+}
+
+func TestCanonicalize_StripSubdomains(t *testing.T) {
+	// Arrange
+	canonicalizer := NewCanonicalizer([]Rule{{StripSubdomains: []string{"m"}}})
+
+	// Act
+	stripped, strippedErr := canonicalizer.Canonicalize("https://m.example.com/article")
+	secondLevel, secondLevelErr := canonicalizer.Canonicalize("https://m.com/article")
+
+	// Assert
+	assert.NoError(t, strippedErr)
+	assert.Equal(t, "https://example.com/article", stripped)
+	assert.NoError(t, secondLevelErr)
+	assert.Equal(t, "https://m.com/article", secondLevel)
+}
+
+func TestCanonicalize_InvalidUrl(t *testing.T) {
+	// Arrange
+	canonicalizer := NewCanonicalizer(DefaultRules())
+
+	// Act
+	_, err := canonicalizer.Canonicalize("not a url")
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestLoadRules(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "url_rules.json")
+	content := `[{"domain": "example.org", "strip_params": ["ref"], "keep_trailing_slash": true}]`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	rules, err := LoadRules(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []Rule{{Domain: "example.org", StripParams: []string{"ref"}, KeepTrailingSlash: true}}, rules)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/telegram"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+type Config struct {
+	Levels   []string             `yaml:"levels"`
+	Topics   []string             `yaml:"topics"`
+	Issue    IssueConfig          `yaml:"issue"`
+	Messages map[string]yaml.Node `yaml:"messages"`
+
+	Catalogs       map[string]*telegram.Messages `yaml:"-"`
+	IssueTemplates *github.IssueTemplates        `yaml:"-"`
+}
+
+// IssueConfig has the text/template templates of the issue, see github.ArticleIssue for the available fields.
+type IssueConfig struct {
+	Title   string `yaml:"title"`
+	Body    string `yaml:"body"`
+	Comment string `yaml:"comment"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	config := &Config{
+		Levels:   []string{"beginner", "medium", "advanced"},
+		Catalogs: telegram.DefaultCatalogs(),
+	}
+
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open config file: %w", err)
+		}
+		defer file.Close()
+
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+		if err = decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("cannot parse config file %s: %w", path, err)
+		}
+	}
+
+	for i := range config.Topics {
+		config.Topics[i] = strings.TrimSpace(config.Topics[i])
+	}
+	if !validLabelValues(config.Levels) || len(config.Levels) == 0 {
+		return nil, fmt.Errorf("invalid levels %q in config file, expected distinct levels up to %d characters without \",\", \":\" and \"|\"", config.Levels, maxLabelValueLength)
+	}
+	if !validLabelValues(config.Topics) {
+		return nil, fmt.Errorf("invalid topics %q in config file, expected distinct topics up to %d characters without \",\", \":\" and \"|\"", config.Topics, maxLabelValueLength)
+	}
+	if err := loadCatalogs(config.Catalogs, config.Messages); err != nil {
+		return nil, err
+	}
+
+	issueTemplates, err := github.NewIssueTemplates(config.Issue.Title, config.Issue.Body, config.Issue.Comment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issue in config file: %w", err)
+	}
+	config.IssueTemplates = issueTemplates
+
+	return config, nil
+}
+
+func loadCatalogs(catalogs map[string]*telegram.Messages, nodes map[string]yaml.Node) error {
+	languages := make([]string, 0, len(nodes))
+	for language := range nodes {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	for _, language := range languages {
+		if !languagePattern.MatchString(language) {
+			return fmt.Errorf("invalid language %q in config file, expected a lowercase language code such as \"en\"", language)
+		}
+
+		messages, ok := catalogs[language]
+		if !ok {
+			messages = telegram.DefaultMessages()
+			catalogs[language] = messages
+		}
+
+		// yaml.Node.Decode ignores unknown fields, so the node is decoded again to report misspelled messages.
+		node := nodes[language]
+		data, err := yaml.Marshal(&node)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s messages in config file: %w", language, err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(messages); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("cannot parse %s messages in config file: %w", language, err)
+		}
+	}
+
+	for language, messages := range catalogs {
+		if err := messages.Validate(); err != nil {
+			return fmt.Errorf("invalid %s messages in config file: %w", language, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/telegram"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(content), 0o600)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	config, err := LoadConfig("")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"beginner", "medium", "advanced"}, config.Levels)
+	assert.Nil(t, config.Topics)
+	assert.Equal(t, telegram.DefaultCatalogs(), config.Catalogs)
+	content, err := config.IssueTemplates.RenderIssue(&github.ArticleIssue{Title: "Sample Title", Author: "Author"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Sample Title / Author", content.Title)
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `
+levels: [junior, middle, senior]
+topics: [kafka, " spark "]
+issue:
+  title: "[{{.Level}}] {{.Title}}"
+messages:
+  en:
+    start: "Hi! Send %s to propose an article."
+    done_button: "Ready"
+  ru:
+    done_button: "Готово!"
+  uk:
+    language_name: "Українська"
+`)
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"junior", "middle", "senior"}, config.Levels)
+	assert.Equal(t, []string{"kafka", "spark"}, config.Topics)
+	assert.Equal(t, "Hi! Send %s to propose an article.", config.Catalogs["en"].Start)
+	assert.Equal(t, "Ready", config.Catalogs["en"].DoneButton)
+	assert.Equal(t, telegram.DefaultMessages().Help, config.Catalogs["en"].Help)
+	assert.Equal(t, "Готово!", config.Catalogs["ru"].DoneButton)
+	assert.Equal(t, telegram.RussianMessages().Start, config.Catalogs["ru"].Start)
+	assert.Equal(t, "Українська", config.Catalogs["uk"].LanguageName)
+	assert.Equal(t, telegram.DefaultMessages().Start, config.Catalogs["uk"].Start)
+
+	content, err := config.IssueTemplates.RenderIssue(&github.ArticleIssue{Title: "Sample Title", Level: "senior"})
+	assert.NoError(t, err)
+	assert.Equal(t, "[senior] Sample Title", content.Title)
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"unknown key", "levls: [junior]", "cannot parse config file"},
+		{"duplicate levels", "levels: [junior, Junior]", "invalid levels [\"junior\" \"Junior\"] in config file"},
+		{"no levels", "levels: []", "invalid levels [] in config file"},
+		{"topic with comma", "topics: [\"kafka, spark\"]", "invalid topics [\"kafka, spark\"] in config file"},
+		{"missing placeholder", "messages:\n  en:\n    issue_created: Done!", "invalid en messages in config file: message \"issue_created\" has placeholders [], expected [%s]"},
+		{"empty message", "messages:\n  ru:\n    cancelled: \"\"", "invalid ru messages in config file: message \"cancelled\" is empty"},
+		{"unknown message", "messages:\n  en:\n    strat: Hi!", "cannot parse en messages in config file"},
+		{"invalid language", "messages:\n  English:\n    start: Hi %s!", "invalid language \"English\" in config file"},
+		{"template", "issue:\n  body: \"{{.Summary}}\"", "invalid issue in config file: invalid issue body template"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tc.content))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "cannot open config file")
+}
+
+func TestNewBotSettingsLevelsOverride(t *testing.T) {
+	config, err := LoadConfig("")
+	assert.NoError(t, err)
+
+	settings := newBotSettings(&Environment{Levels: []string{"intro", "deep-dive"}}, config)
+
+	assert.Equal(t, []string{"intro", "deep-dive"}, settings.Levels)
+	assert.Equal(t, config.Catalogs, settings.Catalogs)
+}
@@ -3,32 +3,104 @@ package main
 import (
 	"errors"
 	"fmt"
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/logging"
 	"github.com/joho/godotenv"
 	"io/fs"
-	"log"
+	"log/slog"
+	"net"
 	"os"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Environment struct {
-	TelegramBotApiToken string
-	PublicUrl           string
-	RapidApiToken       string
-	GitHubToken         string
-	GitHubRepo          string
+	TelegramBotApiToken  string
+	TelegramMode         string
+	RemoveWebhook        bool
+	PublicUrl            string
+	TelegramSecretToken  string
+	ListenAddress        string
+	MetricsListenAddress string
+	TLSCertPath          string
+	TLSKeyPath           string
+	RapidApiToken        string
+	GitHubToken          string
+	GitHubRepo           string
+	GitHubCreateLabels   bool
+	GitHubLabelColor     string
+	GitHubWebhookSecret  string
+	StorageType          string
+	StorageDir           string
+	DraftTimeout         time.Duration
+	UrlRulesPath         string
+	ArticleExtractors    []string
+	ConfigPath           string
+	Levels               []string
+	HttpTimeout          time.Duration
+	RequestTimeout       time.Duration
+	ShutdownTimeout      time.Duration
+	LogFormat            string
+	LogLevel             slog.Level
 }
 
+const (
+	TelegramModeWebhook = "webhook"
+	TelegramModePolling = "polling"
+
+	StorageTypeMemory = "memory"
+	StorageTypeFile   = "file"
+
+	ArticleExtractorRapidApi = "rapidapi"
+	ArticleExtractorHtml     = "html"
+	ArticleExtractorUrl      = extractor.SourceUrl
+
+	// maxLabelValueLength keeps "level:" and "topic:" labels within the GitHub limit of 50 characters.
+	maxLabelValueLength = 44
+)
+
+var (
+	labelColorPattern = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+	// secretTokenPattern is what Telegram accepts as the secret token of a webhook.
+	secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+)
+
 func LoadEnvironment() (*Environment, error) {
 	err := godotenv.Load()
 	if err != nil {
 		var pathError *fs.PathError
 		if !errors.As(err, &pathError) {
-			log.Fatalf("Cannot parse .env file: %s", err.Error())
+			return nil, fmt.Errorf("cannot parse .env file: %w", err)
 		}
 	}
 
-	envVars := map[string]string{"TELEGRAM_BOT_API_TOKEN": "", "PUBLIC_URL": "", "RAPID_API_TOKEN": "", "GITHUB_TOKEN": "", "GITHUB_REPO": ""}
+	articleExtractors, err := parseArticleExtractors(getEnvOrDefault("ARTICLE_EXTRACTORS", "rapidapi,html,url"))
+	if err != nil {
+		return nil, err
+	}
+
+	telegramMode := getEnvOrDefault("TELEGRAM_MODE", TelegramModeWebhook)
+	if telegramMode != TelegramModeWebhook && telegramMode != TelegramModePolling {
+		return nil, fmt.Errorf("invalid TELEGRAM_MODE %q, expected %q or %q", telegramMode, TelegramModeWebhook, TelegramModePolling)
+	}
+
+	removeWebhook, err := strconv.ParseBool(getEnvOrDefault("TELEGRAM_REMOVE_WEBHOOK", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TELEGRAM_REMOVE_WEBHOOK %q, expected \"true\" or \"false\"", os.Getenv("TELEGRAM_REMOVE_WEBHOOK"))
+	}
+
+	envVars := map[string]string{"TELEGRAM_BOT_API_TOKEN": "", "GITHUB_TOKEN": "", "GITHUB_REPO": ""}
+	if telegramMode == TelegramModeWebhook {
+		envVars["PUBLIC_URL"] = ""
+	}
+	for _, articleExtractor := range articleExtractors {
+		if articleExtractor == ArticleExtractorRapidApi {
+			envVars["RAPID_API_TOKEN"] = ""
+		}
+	}
 	var missingVars []string
 	for k := range envVars {
 		envValue, ok := os.LookupEnv(k)
@@ -43,11 +115,164 @@ func LoadEnvironment() (*Environment, error) {
 		return nil, fmt.Errorf("missing environment variables: %s", strings.Join(missingVars, ", "))
 	}
 
+	storageType := getEnvOrDefault("STORAGE_TYPE", StorageTypeMemory)
+	if storageType != StorageTypeMemory && storageType != StorageTypeFile {
+		return nil, fmt.Errorf("invalid STORAGE_TYPE %q, expected %q or %q", storageType, StorageTypeMemory, StorageTypeFile)
+	}
+
+	secretToken := os.Getenv("TELEGRAM_SECRET_TOKEN")
+	if secretToken != "" && !secretTokenPattern.MatchString(secretToken) {
+		return nil, errors.New("invalid TELEGRAM_SECRET_TOKEN, expected 1-256 characters A-Z, a-z, 0-9, \"_\" and \"-\"")
+	}
+
+	listenPort := getEnvOrDefault("LISTEN_PORT", "8080")
+	if port, err := strconv.Atoi(listenPort); err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid LISTEN_PORT %q, expected a port number from 1 to 65535", listenPort)
+	}
+
+	var metricsListenAddress string
+	if metricsPort := os.Getenv("METRICS_PORT"); metricsPort != "" {
+		if port, err := strconv.Atoi(metricsPort); err != nil || port < 1 || port > 65535 || metricsPort == listenPort {
+			return nil, fmt.Errorf("invalid METRICS_PORT %q, expected a port number from 1 to 65535 other than LISTEN_PORT", metricsPort)
+		}
+		metricsListenAddress = net.JoinHostPort(getEnvOrDefault("METRICS_HOST", "localhost"), metricsPort)
+	}
+
+	tlsCertPath, tlsKeyPath := os.Getenv("TLS_CERT_PATH"), os.Getenv("TLS_KEY_PATH")
+	if (tlsCertPath == "") != (tlsKeyPath == "") {
+		return nil, errors.New("TLS_CERT_PATH and TLS_KEY_PATH must be set together")
+	}
+
+	var levels []string
+	if value := os.Getenv("LEVELS"); value != "" {
+		if levels, err = parseLevels(value); err != nil {
+			return nil, err
+		}
+	}
+
+	createLabels, err := strconv.ParseBool(getEnvOrDefault("GITHUB_CREATE_LABELS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GITHUB_CREATE_LABELS %q, expected \"true\" or \"false\"", os.Getenv("GITHUB_CREATE_LABELS"))
+	}
+
+	labelColor := strings.TrimPrefix(getEnvOrDefault("GITHUB_LABEL_COLOR", "ededed"), "#")
+	if !labelColorPattern.MatchString(labelColor) {
+		return nil, fmt.Errorf("invalid GITHUB_LABEL_COLOR %q, expected six hex digits such as \"1d76db\"", os.Getenv("GITHUB_LABEL_COLOR"))
+	}
+
+	draftTimeout, err := getDurationEnvOrDefault("DRAFT_TIMEOUT", "24h")
+	if err != nil {
+		return nil, err
+	}
+
+	httpTimeout, err := getDurationEnvOrDefault("HTTP_TIMEOUT", "10s")
+	if err != nil {
+		return nil, err
+	}
+
+	requestTimeout, err := getDurationEnvOrDefault("REQUEST_TIMEOUT", "30s")
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout, err := getDurationEnvOrDefault("SHUTDOWN_TIMEOUT", "25s")
+	if err != nil {
+		return nil, err
+	}
+
+	logFormat := getEnvOrDefault("LOG_FORMAT", logging.FormatText)
+	if logFormat != logging.FormatText && logFormat != logging.FormatJson {
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q, expected %q or %q", logFormat, logging.FormatText, logging.FormatJson)
+	}
+
+	logLevel, err := logging.ParseLevel(getEnvOrDefault("LOG_LEVEL", "info"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_LEVEL %q, expected \"debug\", \"info\", \"warn\" or \"error\"", os.Getenv("LOG_LEVEL"))
+	}
+
 	return &Environment{
-		TelegramBotApiToken: envVars["TELEGRAM_BOT_API_TOKEN"],
-		PublicUrl:           envVars["PUBLIC_URL"],
-		RapidApiToken:       envVars["RAPID_API_TOKEN"],
-		GitHubToken:         envVars["GITHUB_TOKEN"],
-		GitHubRepo:          envVars["GITHUB_REPO"],
+		TelegramBotApiToken:  envVars["TELEGRAM_BOT_API_TOKEN"],
+		RemoveWebhook:        removeWebhook,
+		TelegramMode:         telegramMode,
+		PublicUrl:            envVars["PUBLIC_URL"],
+		TelegramSecretToken:  secretToken,
+		ListenAddress:        net.JoinHostPort(os.Getenv("LISTEN_HOST"), listenPort),
+		MetricsListenAddress: metricsListenAddress,
+		TLSCertPath:          tlsCertPath,
+		TLSKeyPath:           tlsKeyPath,
+		RapidApiToken:        envVars["RAPID_API_TOKEN"],
+		GitHubToken:          envVars["GITHUB_TOKEN"],
+		GitHubRepo:           envVars["GITHUB_REPO"],
+		GitHubCreateLabels:   createLabels,
+		GitHubLabelColor:     strings.ToLower(labelColor),
+		GitHubWebhookSecret:  os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		StorageType:          storageType,
+		StorageDir:           getEnvOrDefault("STORAGE_DIR", "data"),
+		DraftTimeout:         draftTimeout,
+		UrlRulesPath:         os.Getenv("URL_RULES_PATH"),
+		ArticleExtractors:    articleExtractors,
+		ConfigPath:           os.Getenv("CONFIG_PATH"),
+		Levels:               levels,
+		HttpTimeout:          httpTimeout,
+		RequestTimeout:       requestTimeout,
+		ShutdownTimeout:      shutdownTimeout,
+		LogFormat:            logFormat,
+		LogLevel:             logLevel,
 	}, nil
 }
+
+func getEnvOrDefault(key string, defaultValue string) string {
+	envValue, ok := os.LookupEnv(key)
+	if !ok || len(envValue) == 0 {
+		return defaultValue
+	}
+	return envValue
+}
+
+func getDurationEnvOrDefault(key string, defaultValue string) (time.Duration, error) {
+	value, err := time.ParseDuration(getEnvOrDefault(key, defaultValue))
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a positive duration such as \"30s\" or \"24h\"", key, os.Getenv(key))
+	}
+	return value, nil
+}
+
+func parseArticleExtractors(value string) ([]string, error) {
+	var extractors []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name != ArticleExtractorRapidApi && name != ArticleExtractorHtml && name != ArticleExtractorUrl {
+			return nil, fmt.Errorf("invalid ARTICLE_EXTRACTORS %q, expected a comma separated list of %q, %q and %q", value, ArticleExtractorRapidApi, ArticleExtractorHtml, ArticleExtractorUrl)
+		}
+		if !seen[name] {
+			seen[name] = true
+			extractors = append(extractors, name)
+		}
+	}
+	return extractors, nil
+}
+
+func parseLevels(value string) ([]string, error) {
+	levels := strings.Split(value, ",")
+	for i := range levels {
+		levels[i] = strings.TrimSpace(levels[i])
+	}
+
+	if !validLabelValues(levels) {
+		return nil, fmt.Errorf("invalid LEVELS %q, expected a comma separated list of distinct levels up to %d characters without \":\" and \"|\"", value, maxLabelValueLength)
+	}
+	return levels, nil
+}
+
+func validLabelValues(values []string) bool {
+	seen := make(map[string]bool)
+	for _, value := range values {
+		key := strings.ToLower(value)
+		if value == "" || len(value) > maxLabelValueLength || strings.ContainsAny(value, ",:|") || seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+	return true
+}
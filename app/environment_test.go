@@ -1,8 +1,10 @@
 package main
 
 import (
+	"log/slog"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -35,7 +37,26 @@ func TestLoadEnvironment(t *testing.T) {
 	assert.Equal(t, "rapid_api_token", env.RapidApiToken)
 	assert.Equal(t, "github_token", env.GitHubToken)
 	assert.Equal(t, "github/repo", env.GitHubRepo)
+	assert.Equal(t, "webhook", env.TelegramMode)
+	assert.False(t, env.RemoveWebhook)
 	assert.Equal(t, "https://example.com", env.PublicUrl)
+	assert.Equal(t, "", env.TelegramSecretToken)
+	assert.Equal(t, ":8080", env.ListenAddress)
+	assert.Equal(t, "", env.MetricsListenAddress)
+	assert.Equal(t, "", env.TLSCertPath)
+	assert.Equal(t, "memory", env.StorageType)
+	assert.Equal(t, "data", env.StorageDir)
+	assert.False(t, env.GitHubCreateLabels)
+	assert.Equal(t, "ededed", env.GitHubLabelColor)
+	assert.Equal(t, 24*time.Hour, env.DraftTimeout)
+	assert.Equal(t, []string{"rapidapi", "html", "url"}, env.ArticleExtractors)
+	assert.Equal(t, "", env.ConfigPath)
+	assert.Nil(t, env.Levels)
+	assert.Equal(t, 10*time.Second, env.HttpTimeout)
+	assert.Equal(t, 30*time.Second, env.RequestTimeout)
+	assert.Equal(t, 25*time.Second, env.ShutdownTimeout)
+	assert.Equal(t, "text", env.LogFormat)
+	assert.Equal(t, slog.LevelInfo, env.LogLevel)
 }
 
 func TestLoadEnvironmentMissingToken(t *testing.T) {
@@ -49,3 +70,248 @@ func TestLoadEnvironmentMissingToken(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "missing environment variables: GITHUB_REPO, GITHUB_TOKEN, PUBLIC_URL, RAPID_API_TOKEN, TELEGRAM_BOT_API_TOKEN")
 }
+
+func TestLoadEnvironmentPollingMode(t *testing.T) {
+	t.Setenv("TELEGRAM_MODE", "polling")
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	_ = os.Unsetenv("PUBLIC_URL")
+
+	env, err := LoadEnvironment()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "polling", env.TelegramMode)
+	assert.Equal(t, "", env.PublicUrl)
+}
+
+func TestLoadEnvironmentPollingModeMissingToken(t *testing.T) {
+	t.Setenv("TELEGRAM_MODE", "polling")
+	_ = os.Unsetenv("TELEGRAM_BOT_API_TOKEN")
+	_ = os.Unsetenv("RAPID_API_TOKEN")
+	_ = os.Unsetenv("GITHUB_TOKEN")
+	_ = os.Unsetenv("GITHUB_REPO")
+	_ = os.Unsetenv("PUBLIC_URL")
+
+	_, err := LoadEnvironment()
+	assert.EqualError(t, err, "missing environment variables: GITHUB_REPO, GITHUB_TOKEN, RAPID_API_TOKEN, TELEGRAM_BOT_API_TOKEN")
+}
+
+func TestLoadEnvironmentInvalidTelegramMode(t *testing.T) {
+	t.Setenv("TELEGRAM_MODE", "longpoll")
+
+	_, err := LoadEnvironment()
+	assert.EqualError(t, err, "invalid TELEGRAM_MODE \"longpoll\", expected \"webhook\" or \"polling\"")
+}
+
+func TestLoadEnvironmentFileStorage(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("STORAGE_TYPE", "file")
+	t.Setenv("STORAGE_DIR", "/var/lib/deordie-bot")
+
+	env, err := LoadEnvironment()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "file", env.StorageType)
+	assert.Equal(t, "/var/lib/deordie-bot", env.StorageDir)
+}
+
+func TestLoadEnvironmentInvalidStorageType(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("STORAGE_TYPE", "redis")
+
+	_, err := LoadEnvironment()
+	assert.EqualError(t, err, "invalid STORAGE_TYPE \"redis\", expected \"memory\" or \"file\"")
+}
+
+func TestLoadEnvironmentInvalidDraftTimeout(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("DRAFT_TIMEOUT", "soon")
+
+	_, err := LoadEnvironment()
+	assert.EqualError(t, err, "invalid DRAFT_TIMEOUT \"soon\", expected a positive duration such as \"30s\" or \"24h\"")
+}
+
+func TestLoadEnvironmentLabelSettings(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("GITHUB_CREATE_LABELS", "true")
+	t.Setenv("GITHUB_LABEL_COLOR", "#1D76DB")
+
+	env, err := LoadEnvironment()
+	assert.NoError(t, err)
+
+	assert.True(t, env.GitHubCreateLabels)
+	assert.Equal(t, "1d76db", env.GitHubLabelColor)
+}
+
+func TestLoadEnvironmentInvalidLabelColor(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("GITHUB_LABEL_COLOR", "blue")
+
+	_, err := LoadEnvironment()
+	assert.EqualError(t, err, "invalid GITHUB_LABEL_COLOR \"blue\", expected six hex digits such as \"1d76db\"")
+}
+
+func TestLoadEnvironmentExtractorsWithoutRapidApi(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("ARTICLE_EXTRACTORS", "html, url")
+	_ = os.Unsetenv("RAPID_API_TOKEN")
+
+	env, err := LoadEnvironment()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"html", "url"}, env.ArticleExtractors)
+	assert.Equal(t, "", env.RapidApiToken)
+}
+
+func TestLoadEnvironmentInvalidArticleExtractor(t *testing.T) {
+	t.Setenv("ARTICLE_EXTRACTORS", "html,gpt")
+
+	_, err := LoadEnvironment()
+	assert.EqualError(t, err, "invalid ARTICLE_EXTRACTORS \"html,gpt\", expected a comma separated list of \"rapidapi\", \"html\" and \"url\"")
+}
+
+func TestLoadEnvironmentLevels(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("LEVELS", "junior, middle, senior")
+
+	env, err := LoadEnvironment()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"junior", "middle", "senior"}, env.Levels)
+}
+
+func TestLoadEnvironmentInvalidLevels(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("LEVELS", "junior,Junior,,senior")
+
+	_, err := LoadEnvironment()
+	assert.EqualError(t, err, "invalid LEVELS \"junior,Junior,,senior\", expected a comma separated list of distinct levels up to 44 characters without \":\" and \"|\"")
+}
+
+func TestLoadEnvironmentServerSettings(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("TELEGRAM_SECRET_TOKEN", "s3cr3t_T0ken-1")
+	t.Setenv("LISTEN_HOST", "127.0.0.1")
+	t.Setenv("LISTEN_PORT", "8443")
+	t.Setenv("METRICS_PORT", "9090")
+	t.Setenv("TLS_CERT_PATH", "/etc/deordie-bot/cert.pem")
+	t.Setenv("TLS_KEY_PATH", "/etc/deordie-bot/key.pem")
+
+	env, err := LoadEnvironment()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "s3cr3t_T0ken-1", env.TelegramSecretToken)
+	assert.Equal(t, "127.0.0.1:8443", env.ListenAddress)
+	assert.Equal(t, "localhost:9090", env.MetricsListenAddress)
+	assert.Equal(t, "/etc/deordie-bot/cert.pem", env.TLSCertPath)
+	assert.Equal(t, "/etc/deordie-bot/key.pem", env.TLSKeyPath)
+}
+
+func TestLoadEnvironmentInvalidServerSettings(t *testing.T) {
+	testCases := []struct {
+		name     string
+		key      string
+		value    string
+		expected string
+	}{
+		{"secret token characters", "TELEGRAM_SECRET_TOKEN", "secret token!", "invalid TELEGRAM_SECRET_TOKEN, expected 1-256 characters A-Z, a-z, 0-9, \"_\" and \"-\""},
+		{"port out of range", "LISTEN_PORT", "70000", "invalid LISTEN_PORT \"70000\", expected a port number from 1 to 65535"},
+		{"port not a number", "LISTEN_PORT", "http", "invalid LISTEN_PORT \"http\", expected a port number from 1 to 65535"},
+		{"remove webhook not a bool", "TELEGRAM_REMOVE_WEBHOOK", "yes", "invalid TELEGRAM_REMOVE_WEBHOOK \"yes\", expected \"true\" or \"false\""},
+		{"metrics port same as listen port", "METRICS_PORT", "8080", "invalid METRICS_PORT \"8080\", expected a port number from 1 to 65535 other than LISTEN_PORT"},
+		{"certificate without key", "TLS_CERT_PATH", "/etc/deordie-bot/cert.pem", "TLS_CERT_PATH and TLS_KEY_PATH must be set together"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+			t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+			t.Setenv("GITHUB_TOKEN", "github_token")
+			t.Setenv("GITHUB_REPO", "github/repo")
+			t.Setenv("PUBLIC_URL", "https://example.com")
+			t.Setenv(tc.key, tc.value)
+
+			_, err := LoadEnvironment()
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestLoadEnvironmentLogSettings(t *testing.T) {
+	t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+	t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+	t.Setenv("GITHUB_TOKEN", "github_token")
+	t.Setenv("GITHUB_REPO", "github/repo")
+	t.Setenv("PUBLIC_URL", "https://example.com")
+	t.Setenv("LOG_FORMAT", "json")
+	t.Setenv("LOG_LEVEL", "DEBUG")
+
+	env, err := LoadEnvironment()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "json", env.LogFormat)
+	assert.Equal(t, slog.LevelDebug, env.LogLevel)
+}
+
+func TestLoadEnvironmentInvalidLogSettings(t *testing.T) {
+	testCases := []struct {
+		name     string
+		key      string
+		value    string
+		expected string
+	}{
+		{"format", "LOG_FORMAT", "logfmt", "invalid LOG_FORMAT \"logfmt\", expected \"text\" or \"json\""},
+		{"level", "LOG_LEVEL", "verbose", "invalid LOG_LEVEL \"verbose\", expected \"debug\", \"info\", \"warn\" or \"error\""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("TELEGRAM_BOT_API_TOKEN", "telegram_token")
+			t.Setenv("RAPID_API_TOKEN", "rapid_api_token")
+			t.Setenv("GITHUB_TOKEN", "github_token")
+			t.Setenv("GITHUB_REPO", "github/repo")
+			t.Setenv("PUBLIC_URL", "https://example.com")
+			t.Setenv(tc.key, tc.value)
+
+			_, err := LoadEnvironment()
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package extractor
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	FieldTitle        = "title"
+	FieldDate         = "date"
+	FieldAuthor       = "author"
+	FieldLanguage     = "language"
+	FieldUrl          = "url"
+	FieldEffectiveUrl = "effective_url"
+	FieldDomain       = "domain"
+
+	SourceUrl = "url"
+)
+
+type Article struct {
+	Title        string
+	Date         time.Time
+	Author       string
+	Language     string
+	Url          string
+	EffectiveUrl string
+	Domain       string
+	Sources      map[string]string `json:",omitempty"`
+}
+
+func (a *Article) TitleGuessed() bool {
+	return a.Sources[FieldTitle] == SourceUrl
+}
+
+type Extractor interface {
+	ExtractArticle(ctx context.Context, articleUrl string) (*Article, error)
+}
+
+type Checker interface {
+	Check(ctx context.Context) error
+}
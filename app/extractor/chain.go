@@ -0,0 +1,128 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+type NamedExtractor struct {
+	Name      string
+	Extractor Extractor
+}
+
+type Chain struct {
+	extractors []NamedExtractor
+}
+
+func NewChain(extractors ...NamedExtractor) *Chain {
+	return &Chain{
+		extractors: extractors,
+	}
+}
+
+func (c *Chain) ExtractArticle(ctx context.Context, articleUrl string) (*Article, error) {
+	merged := &Article{Sources: make(map[string]string)}
+	var errs []error
+
+	for i, named := range c.extractors {
+		if ctx.Err() != nil && !isLocal(named) {
+			errs = append(errs, fmt.Errorf("%s: %w", named.Name, ctx.Err()))
+			continue
+		}
+
+		article, err := c.extract(ctx, c.extractors[i:], articleUrl)
+		if err != nil {
+			slog.WarnContext(ctx, "Article extractor failed", "extractor", named.Name, "url", articleUrl, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", named.Name, err))
+			continue
+		}
+
+		merged.merge(article, named.Name)
+		if merged.isComplete() {
+			break
+		}
+	}
+
+	if merged.Title == "" {
+		if len(errs) == 0 {
+			return nil, fmt.Errorf("no article title found for %s", articleUrl)
+		}
+		return nil, fmt.Errorf("all article extractors failed: %w", errors.Join(errs...))
+	}
+
+	return merged, nil
+}
+
+func (c *Chain) Check(ctx context.Context) error {
+	var errs []error
+	for _, named := range c.extractors {
+		checker, ok := named.Extractor.(Checker)
+		if !ok {
+			return nil
+		}
+
+		err := checker.Check(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", named.Name, err))
+	}
+
+	if len(errs) == 0 {
+		return errors.New("no article extractors configured")
+	}
+	return fmt.Errorf("all article extractors are unreachable: %w", errors.Join(errs...))
+}
+
+func (c *Chain) extract(ctx context.Context, remaining []NamedExtractor, articleUrl string) (*Article, error) {
+	named := remaining[0]
+	deadline, ok := ctx.Deadline()
+	if !ok || isLocal(named) {
+		return named.Extractor.ExtractArticle(ctx, articleUrl)
+	}
+
+	shares := 0
+	for _, next := range remaining {
+		if !isLocal(next) {
+			shares++
+		}
+	}
+
+	extractCtx, cancel := context.WithTimeout(ctx, time.Until(deadline)/time.Duration(shares))
+	defer cancel()
+	return named.Extractor.ExtractArticle(extractCtx, articleUrl)
+}
+
+// isLocal reports whether the extractor makes no network calls, so it runs even when the time is up.
+func isLocal(named NamedExtractor) bool {
+	_, ok := named.Extractor.(*UrlExtractor)
+	return ok
+}
+
+func (a *Article) merge(other *Article, source string) {
+	mergeString(&a.Title, other.Title, FieldTitle, source, a.Sources)
+	mergeString(&a.Author, other.Author, FieldAuthor, source, a.Sources)
+	mergeString(&a.Language, other.Language, FieldLanguage, source, a.Sources)
+	mergeString(&a.Url, other.Url, FieldUrl, source, a.Sources)
+	mergeString(&a.EffectiveUrl, other.EffectiveUrl, FieldEffectiveUrl, source, a.Sources)
+	mergeString(&a.Domain, other.Domain, FieldDomain, source, a.Sources)
+	if a.Date.IsZero() && !other.Date.IsZero() {
+		a.Date = other.Date
+		a.Sources[FieldDate] = source
+	}
+}
+
+func (a *Article) isComplete() bool {
+	return a.Title != "" && !a.Date.IsZero() && a.Author != "" && a.Language != "" &&
+		a.Url != "" && a.EffectiveUrl != "" && a.Domain != ""
+}
+
+func mergeString(target *string, value string, field string, source string, sources map[string]string) {
+	if *target == "" && value != "" {
+		*target = value
+		sources[field] = source
+	}
+}
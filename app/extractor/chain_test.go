@@ -0,0 +1,173 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockExtractor struct {
+	mock.Mock
+}
+
+func (m *MockExtractor) ExtractArticle(_ context.Context, articleUrl string) (*Article, error) {
+	args := m.Called(articleUrl)
+	return args.Get(0).(*Article), args.Error(1)
+}
+
+func TestChain_MergesPartialResults(t *testing.T) {
+	// Arrange
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	first := new(MockExtractor)
+	first.On("ExtractArticle", "https://example.com/article").Return(&Article{Title: "Article Title", Url: "https://example.com/article"}, nil)
+	second := new(MockExtractor)
+	second.On("ExtractArticle", "https://example.com/article").Return(&Article{Title: "Other Title", Author: "Jane Doe", Date: date, Language: "en", EffectiveUrl: "https://example.com/article", Domain: "example.com"}, nil)
+	third := new(MockExtractor)
+	chain := NewChain(NamedExtractor{"first", first}, NamedExtractor{"second", second}, NamedExtractor{"third", third})
+
+	// Act
+	article, err := chain.ExtractArticle(context.Background(), "https://example.com/article")
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	expectedArticle := &Article{
+		Title:        "Article Title",
+		Date:         date,
+		Author:       "Jane Doe",
+		Language:     "en",
+		Url:          "https://example.com/article",
+		EffectiveUrl: "https://example.com/article",
+		Domain:       "example.com",
+		Sources: map[string]string{
+			FieldTitle:        "first",
+			FieldUrl:          "first",
+			FieldAuthor:       "second",
+			FieldDate:         "second",
+			FieldLanguage:     "second",
+			FieldEffectiveUrl: "second",
+			FieldDomain:       "second",
+		},
+	}
+	assert.Equal(t, expectedArticle, article, "unexpected merged article")
+	third.AssertNotCalled(t, "ExtractArticle", mock.Anything)
+}
+
+func TestChain_SkipsFailedExtractors(t *testing.T) {
+	// Arrange
+	failing := new(MockExtractor)
+	failing.On("ExtractArticle", mock.Anything).Return((*Article)(nil), fmt.Errorf("quota exceeded"))
+	fallback := new(MockExtractor)
+	fallback.On("ExtractArticle", mock.Anything).Return(&Article{Title: "Article Title", Domain: "example.com"}, nil)
+	chain := NewChain(NamedExtractor{"rapidapi", failing}, NamedExtractor{"url", fallback})
+
+	// Act
+	article, err := chain.ExtractArticle(context.Background(), "https://example.com/article")
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Article Title", article.Title)
+	assert.Equal(t, map[string]string{FieldTitle: "url", FieldDomain: "url"}, article.Sources)
+	assert.True(t, article.TitleGuessed(), "Expected the title taken from the URL to be guessed")
+}
+
+func TestChain_AllExtractorsFailed(t *testing.T) {
+	// Arrange
+	first := new(MockExtractor)
+	first.On("ExtractArticle", mock.Anything).Return((*Article)(nil), fmt.Errorf("quota exceeded"))
+	second := new(MockExtractor)
+	second.On("ExtractArticle", mock.Anything).Return((*Article)(nil), fmt.Errorf("paywall"))
+	chain := NewChain(NamedExtractor{"rapidapi", first}, NamedExtractor{"html", second})
+
+	// Act
+	_, err := chain.ExtractArticle(context.Background(), "https://example.com/article")
+
+	// Assert
+	assert.NotNil(t, err, "expected non-nil error")
+	assert.EqualError(t, err, "all article extractors failed: rapidapi: quota exceeded\nhtml: paywall", "unexpected error message")
+}
+
+type MockCheckedExtractor struct {
+	MockExtractor
+}
+
+func (m *MockCheckedExtractor) Check(_ context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestChain_Check(t *testing.T) {
+	// Arrange
+	failing := new(MockCheckedExtractor)
+	failing.On("Check").Return(fmt.Errorf("connection refused"))
+	passing := new(MockCheckedExtractor)
+	passing.On("Check").Return(nil)
+	unchecked := new(MockExtractor)
+
+	testCases := []struct {
+		name     string
+		chain    *Chain
+		expected string
+	}{
+		{"one reachable", NewChain(NamedExtractor{"rapidapi", failing}, NamedExtractor{"backup", passing}), ""},
+		{"unchecked fallback", NewChain(NamedExtractor{"rapidapi", failing}, NamedExtractor{"html", unchecked}), ""},
+		{"all unreachable", NewChain(NamedExtractor{"rapidapi", failing}), "all article extractors are unreachable: rapidapi: connection refused"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			err := tc.chain.Check(context.Background())
+
+			// Assert
+			if tc.expected == "" {
+				assert.Nil(t, err, "unexpected error")
+			} else {
+				assert.EqualError(t, err, tc.expected, "unexpected error message")
+			}
+		})
+	}
+}
+
+// blockingExtractor stands for a hung provider, it returns only when its context is done.
+type blockingExtractor struct{}
+
+func (e *blockingExtractor) ExtractArticle(ctx context.Context, _ string) (*Article, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestChain_LeavesTimeForFallbacks(t *testing.T) {
+	// Arrange
+	fallback := new(MockExtractor)
+	fallback.On("ExtractArticle", "https://example.com/article").Return(&Article{Title: "Article Title", Author: "Jane Doe"}, nil)
+	chain := NewChain(NamedExtractor{"rapidapi", &blockingExtractor{}}, NamedExtractor{"html", fallback}, NamedExtractor{"url", NewUrlExtractor()})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Act
+	article, err := chain.ExtractArticle(ctx, "https://example.com/article")
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Article Title", article.Title)
+	assert.Equal(t, "Jane Doe", article.Author)
+	assert.Equal(t, "example.com", article.Domain)
+}
+
+func TestChain_RunsUrlExtractorWhenTimeIsUp(t *testing.T) {
+	// Arrange
+	chain := NewChain(NamedExtractor{"rapidapi", &blockingExtractor{}}, NamedExtractor{"url", NewUrlExtractor()})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	article, err := chain.ExtractArticle(ctx, "https://example.com/my-article")
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "My article", article.Title)
+}
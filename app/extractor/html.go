@@ -0,0 +1,308 @@
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxPageSize limits how much of a page is read, metadata lives in the head anyway.
+const maxPageSize = 5 << 20
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+var jsonLdArticleTypes = map[string]bool{
+	"Article":             true,
+	"NewsArticle":         true,
+	"BlogPosting":         true,
+	"TechArticle":         true,
+	"ScholarlyArticle":    true,
+	"Report":              true,
+	"AnalysisNewsArticle": true,
+}
+
+type HtmlExtractor struct {
+	httpClient *http.Client
+}
+
+type pageMetadata struct {
+	title         string
+	htmlLang      string
+	canonicalUrl  string
+	meta          map[string]string
+	jsonLdArticle *jsonLdArticle
+}
+
+type jsonLdArticle struct {
+	Headline      string          `json:"headline"`
+	Name          string          `json:"name"`
+	Author        json.RawMessage `json:"author"`
+	DatePublished string          `json:"datePublished"`
+	InLanguage    json.RawMessage `json:"inLanguage"`
+}
+
+func wrapHtmlError(err error) error {
+	return fmt.Errorf("error occurred during ExtractArticle call: %w", err)
+}
+
+func NewHtmlExtractor(httpClient *http.Client) *HtmlExtractor {
+	return &HtmlExtractor{
+		httpClient: httpClient,
+	}
+}
+
+func (e *HtmlExtractor) ExtractArticle(ctx context.Context, articleUrl string) (*Article, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", articleUrl, nil)
+	if err != nil {
+		return nil, wrapHtmlError(err)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme in ExtractArticle call: %q", req.URL.Scheme)
+	}
+
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; DEorDIEBot/1.0; +https://t.me/deordie_bot)")
+
+	res, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, wrapHtmlError(err)
+	}
+
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("non-successful HTTP status code in ExtractArticle call: %d", res.StatusCode)
+	}
+
+	doc, err := html.Parse(io.LimitReader(res.Body, maxPageSize))
+	if err != nil {
+		return nil, wrapHtmlError(err)
+	}
+
+	metadata := &pageMetadata{meta: make(map[string]string)}
+	metadata.collect(doc)
+
+	effectiveUrl := firstNonEmpty(resolveUrl(res.Request.URL, metadata.canonicalUrl), resolveUrl(res.Request.URL, metadata.meta["og:url"]), res.Request.URL.String())
+	article := &Article{
+		Title:        metadata.articleTitle(),
+		Date:         metadata.articleDate(),
+		Author:       metadata.articleAuthor(),
+		Language:     metadata.articleLanguage(),
+		Url:          articleUrl,
+		EffectiveUrl: effectiveUrl,
+		Domain:       res.Request.URL.Hostname(),
+	}
+
+	if article.Title == "" {
+		return nil, fmt.Errorf("no title found on page %s", articleUrl)
+	}
+
+	return article, nil
+}
+
+func (m *pageMetadata) collect(n *html.Node) {
+	if n.Type == html.ElementNode {
+		switch n.Data {
+		case "html":
+			m.htmlLang = attr(n, "lang")
+		case "title":
+			if m.title == "" && n.FirstChild != nil {
+				m.title = strings.TrimSpace(n.FirstChild.Data)
+			}
+		case "meta":
+			key := strings.ToLower(firstNonEmpty(attr(n, "property"), attr(n, "name"), attr(n, "http-equiv")))
+			content := strings.TrimSpace(attr(n, "content"))
+			if _, ok := m.meta[key]; key != "" && content != "" && !ok {
+				m.meta[key] = content
+			}
+		case "link":
+			if strings.EqualFold(attr(n, "rel"), "canonical") && m.canonicalUrl == "" {
+				m.canonicalUrl = attr(n, "href")
+			}
+		case "script":
+			if strings.EqualFold(attr(n, "type"), "application/ld+json") && m.jsonLdArticle == nil && n.FirstChild != nil {
+				m.jsonLdArticle = findJsonLdArticle([]byte(n.FirstChild.Data))
+			}
+		}
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		m.collect(child)
+	}
+}
+
+func (m *pageMetadata) articleTitle() string {
+	var headline string
+	if m.jsonLdArticle != nil {
+		headline = firstNonEmpty(m.jsonLdArticle.Headline, m.jsonLdArticle.Name)
+	}
+	return strings.TrimSpace(firstNonEmpty(headline, m.meta["og:title"], m.meta["twitter:title"], m.title))
+}
+
+func (m *pageMetadata) articleAuthor() string {
+	var author string
+	if m.jsonLdArticle != nil {
+		author = parseJsonLdAuthor(m.jsonLdArticle.Author)
+	}
+
+	// article:author is often a profile URL rather than a name.
+	articleAuthor := m.meta["article:author"]
+	if strings.HasPrefix(articleAuthor, "http://") || strings.HasPrefix(articleAuthor, "https://") {
+		articleAuthor = ""
+	}
+
+	return firstNonEmpty(author, m.meta["author"], articleAuthor, m.meta["twitter:creator"])
+}
+
+func (m *pageMetadata) articleDate() time.Time {
+	var published string
+	if m.jsonLdArticle != nil {
+		published = m.jsonLdArticle.DatePublished
+	}
+
+	value := firstNonEmpty(published, m.meta["article:published_time"], m.meta["date"], m.meta["pubdate"])
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date
+		}
+	}
+	return time.Time{}
+}
+
+func (m *pageMetadata) articleLanguage() string {
+	var inLanguage string
+	if m.jsonLdArticle != nil {
+		_ = json.Unmarshal(m.jsonLdArticle.InLanguage, &inLanguage)
+	}
+
+	language := firstNonEmpty(inLanguage, m.htmlLang, m.meta["og:locale"], m.meta["content-language"])
+	// Keep the primary language subtag only, e.g. "en-US" and "en_US" become "en".
+	language, _, _ = strings.Cut(strings.ReplaceAll(language, "_", "-"), "-")
+	return strings.ToLower(strings.TrimSpace(language))
+}
+
+func findJsonLdArticle(content []byte) *jsonLdArticle {
+	var nodes []json.RawMessage
+	if err := json.Unmarshal(content, &nodes); err != nil {
+		var single json.RawMessage
+		if err = json.Unmarshal(content, &single); err != nil {
+			return nil
+		}
+		nodes = []json.RawMessage{single}
+	}
+
+	for _, node := range nodes {
+		var typed struct {
+			Type  json.RawMessage   `json:"@type"`
+			Graph []json.RawMessage `json:"@graph"`
+		}
+		if err := json.Unmarshal(node, &typed); err != nil {
+			continue
+		}
+
+		if len(typed.Graph) > 0 {
+			graph, _ := json.Marshal(typed.Graph)
+			if article := findJsonLdArticle(graph); article != nil {
+				return article
+			}
+			continue
+		}
+
+		if isJsonLdArticleType(typed.Type) {
+			var article jsonLdArticle
+			if err := json.Unmarshal(node, &article); err == nil {
+				return &article
+			}
+		}
+	}
+
+	return nil
+}
+
+func isJsonLdArticleType(raw json.RawMessage) bool {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return jsonLdArticleTypes[single]
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(raw, &multiple); err == nil {
+		for _, t := range multiple {
+			if jsonLdArticleTypes[t] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseJsonLdAuthor(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return strings.TrimSpace(name)
+	}
+
+	var person struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &person); err == nil {
+		return strings.TrimSpace(person.Name)
+	}
+
+	var authors []json.RawMessage
+	if err := json.Unmarshal(raw, &authors); err == nil {
+		names := make([]string, 0, len(authors))
+		for _, author := range authors {
+			if name = parseJsonLdAuthor(author); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+
+	return ""
+}
+
+func resolveUrl(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return resolved.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,171 @@
+package extractor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFixtureServer(t *testing.T, fixture string) *httptest.Server {
+	content, err := os.ReadFile(filepath.Join("testdata", fixture))
+	assert.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "unexpected HTTP method")
+		assert.NotEmpty(t, r.Header.Get("User-Agent"), "expected User-Agent header")
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(content)
+	}))
+}
+
+func TestHtmlExtractor_JsonLd(t *testing.T) {
+	// Arrange
+	mockServer := newFixtureServer(t, "json_ld.html")
+	defer mockServer.Close()
+	extractor := NewHtmlExtractor(mockServer.Client())
+
+	// Act
+	article, err := extractor.ExtractArticle(context.Background(), mockServer.URL+"/blog/lakehouse?utm_source=telegram")
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "How we built our lakehouse", article.Title)
+	assert.Equal(t, "Jane Doe, John Smith", article.Author)
+	assert.True(t, time.Date(2023, 11, 5, 7, 30, 0, 0, time.UTC).Equal(article.Date), "unexpected date %s", article.Date)
+	assert.Equal(t, "en", article.Language)
+	assert.Equal(t, mockServer.URL+"/blog/lakehouse?utm_source=telegram", article.Url)
+	assert.Equal(t, mockServer.URL+"/blog/lakehouse", article.EffectiveUrl)
+	assert.Equal(t, "127.0.0.1", article.Domain)
+}
+
+func TestHtmlExtractor_OpenGraph(t *testing.T) {
+	// Arrange
+	mockServer := newFixtureServer(t, "open_graph.html")
+	defer mockServer.Close()
+	extractor := NewHtmlExtractor(mockServer.Client())
+
+	// Act
+	article, err := extractor.ExtractArticle(context.Background(), mockServer.URL)
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	expectedArticle := &Article{
+		Title:        "Streaming joins explained",
+		Date:         time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Author:       "@ivan",
+		Language:     "ru",
+		Url:          mockServer.URL,
+		EffectiveUrl: "https://blog.example.com/streaming-joins",
+		Domain:       "127.0.0.1",
+	}
+	assert.Equal(t, expectedArticle, article, "unexpected extracted article")
+}
+
+func TestHtmlExtractor_TitleOnly(t *testing.T) {
+	// Arrange
+	mockServer := newFixtureServer(t, "title_only.html")
+	defer mockServer.Close()
+	extractor := NewHtmlExtractor(mockServer.Client())
+
+	// Act
+	article, err := extractor.ExtractArticle(context.Background(), mockServer.URL)
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Plain page title", article.Title)
+	assert.Equal(t, "Plain Author", article.Author)
+	assert.Equal(t, "", article.Language)
+	assert.True(t, article.Date.IsZero(), "expected zero date")
+}
+
+func TestHtmlExtractor_NoTitle(t *testing.T) {
+	// Arrange
+	mockServer := newFixtureServer(t, "no_title.html")
+	defer mockServer.Close()
+	extractor := NewHtmlExtractor(mockServer.Client())
+
+	// Act
+	_, err := extractor.ExtractArticle(context.Background(), mockServer.URL)
+
+	// Assert
+	assert.NotNil(t, err, "expected non-nil error")
+	assert.EqualError(t, err, "no title found on page "+mockServer.URL, "unexpected error message")
+}
+
+func TestHtmlExtractor_NonSuccessHTTPStatus(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer mockServer.Close()
+	extractor := NewHtmlExtractor(mockServer.Client())
+
+	// Act
+	_, err := extractor.ExtractArticle(context.Background(), mockServer.URL)
+
+	// Assert
+	assert.NotNil(t, err, "expected non-nil error")
+	assert.EqualError(t, err, "non-successful HTTP status code in ExtractArticle call: 404", "unexpected error message")
+}
+
+func TestHtmlExtractor_UnsupportedScheme(t *testing.T) {
+	// Arrange
+	extractor := NewHtmlExtractor(&http.Client{Transport: NewPublicTransport()})
+
+	// Act
+	_, err := extractor.ExtractArticle(context.Background(), "file:///etc/passwd")
+
+	// Assert
+	assert.EqualError(t, err, "unsupported URL scheme in ExtractArticle call: \"file\"")
+}
+
+func TestHtmlExtractor_RefusesNonPublicAddress(t *testing.T) {
+	// Arrange
+	mockServer := newFixtureServer(t, "open_graph.html")
+	defer mockServer.Close()
+	extractor := NewHtmlExtractor(&http.Client{Transport: NewPublicTransport()})
+
+	// Act
+	_, err := extractor.ExtractArticle(context.Background(), mockServer.URL)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNonPublicAddress)
+}
+
+func TestIsPublic(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ip       string
+		expected bool
+	}{
+		{"public IPv4", "93.184.216.34", true},
+		{"public IPv6", "2606:2800:220:1:248:1893:25c8:1946", true},
+		{"loopback", "127.0.0.1", false},
+		{"IPv6 loopback", "::1", false},
+		{"private", "10.1.2.3", false},
+		{"cloud metadata", "169.254.169.254", false},
+		{"unspecified", "0.0.0.0", false},
+		{"carrier-grade NAT", "100.64.0.1", false},
+		{"IPv4-mapped loopback", "::ffff:127.0.0.1", false},
+		{"IPv6 unique local", "fd00::1", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual := isPublic(netip.MustParseAddr(tc.ip))
+
+			// Assert
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	slugSeparators = regexp.MustCompile(`[-_+]+`)
+	// slugSuffixes matches file extensions and trailing hash-like ids, e.g. "-3f2a9c1b7e4d" in Medium slugs.
+	slugSuffixes = regexp.MustCompile(`(\.[a-z0-9]{2,5}|-[0-9a-f]{8,})$`)
+)
+
+type UrlExtractor struct{}
+
+func NewUrlExtractor() *UrlExtractor {
+	return &UrlExtractor{}
+}
+
+func (e *UrlExtractor) ExtractArticle(_ context.Context, articleUrl string) (*Article, error) {
+	u, err := url.Parse(articleUrl)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("can't extract article from invalid URL %q", articleUrl)
+	}
+
+	return &Article{
+		Title:        titleFromPath(u),
+		Url:          articleUrl,
+		EffectiveUrl: articleUrl,
+		Domain:       u.Hostname(),
+	}, nil
+}
+
+func titleFromPath(u *url.URL) string {
+	slug := path.Base(strings.TrimRight(u.Path, "/"))
+	if slug == "." || slug == "/" || slug == "" {
+		return u.Hostname()
+	}
+
+	slug = slugSuffixes.ReplaceAllString(strings.ToLower(slug), "")
+	title := strings.TrimSpace(slugSeparators.ReplaceAllString(slug, " "))
+	if title == "" {
+		return u.Hostname()
+	}
+
+	first, size := utf8.DecodeRuneInString(title)
+	return string(unicode.ToUpper(first)) + title[size:]
+}
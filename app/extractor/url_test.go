@@ -0,0 +1,48 @@
+package extractor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUrlExtractor(t *testing.T) {
+	testCases := []struct {
+		name          string
+		url           string
+		expectedTitle string
+	}{
+		{"slug", "https://example.com/blog/how-we-built-our-lakehouse/", "How we built our lakehouse"},
+		{"medium slug with id", "https://medium.com/@jane/streaming-joins-explained-3f2a9c1b7e4d", "Streaming joins explained"},
+		{"file extension", "https://example.com/posts/kafka_internals.html", "Kafka internals"},
+		{"root", "https://example.com/", "example.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			extractor := NewUrlExtractor()
+
+			// Act
+			article, err := extractor.ExtractArticle(context.Background(), tc.url)
+
+			// Assert
+			assert.Nil(t, err, "unexpected error")
+			assert.Equal(t, tc.expectedTitle, article.Title)
+			assert.Equal(t, tc.url, article.Url)
+			assert.Equal(t, tc.url, article.EffectiveUrl)
+		})
+	}
+}
+
+func TestUrlExtractor_InvalidUrl(t *testing.T) {
+	// Arrange
+	extractor := NewUrlExtractor()
+
+	// Act
+	_, err := extractor.ExtractArticle(context.Background(), "not a url")
+
+	// Assert
+	assert.NotNil(t, err, "expected non-nil error")
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type APIError struct {
+	Operation        string       `json:"-"`
+	StatusCode       int          `json:"-"`
+	Message          string       `json:"message"`
+	DocumentationUrl string       `json:"documentation_url"`
+	Errors           []FieldError `json:"errors"`
+}
+
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+func newAPIError(operation string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{}
+	_ = json.Unmarshal(body, apiErr)
+	apiErr.Operation = operation
+	apiErr.StatusCode = statusCode
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	text := fmt.Sprintf("non-successful HTTP status code in %s call: %d", e.Operation, e.StatusCode)
+	if reason := e.Reason(); reason != "" {
+		text += ", " + reason
+	}
+	if e.DocumentationUrl != "" {
+		text += " (see " + e.DocumentationUrl + ")"
+	}
+	return text
+}
+
+func (e *APIError) Reason() string {
+	var details []string
+	for _, fieldErr := range e.Errors {
+		if detail := fieldErr.String(); detail != "" {
+			details = append(details, detail)
+		}
+	}
+
+	switch {
+	case len(details) == 0:
+		return e.Message
+	case e.Message == "":
+		return strings.Join(details, "; ")
+	default:
+		return e.Message + ": " + strings.Join(details, "; ")
+	}
+}
+
+func (e FieldError) String() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Field == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s %s", e.Field, e.Code)
+}
+
+// UnmarshalJSON accepts plain strings too, some endpoints report errors without the field details.
+func (e *FieldError) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = FieldError{Message: message}
+		return nil
+	}
+
+	type fieldError FieldError
+	return json.Unmarshal(data, (*fieldError)(e))
+}
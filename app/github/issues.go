@@ -2,18 +2,35 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/deordie/deordie-bot/app/retry"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Client struct {
 	githubToken string
 	owner       string
 	repo        string
+	repoUrl     string
 	issuesUrl   string
+	searchUrl   string
+	labelsUrl   string
+	httpClient  *http.Client
+	retryPolicy retry.Policy
+
+	labelSettings LabelSettings
+	templates     *IssueTemplates
+
+	rateLimitMutex   sync.Mutex
+	rateLimitResetAt map[string]time.Time
 }
 
 type ArticleIssue struct {
@@ -26,22 +43,56 @@ type ArticleIssue struct {
 	User        string
 }
 
+const (
+	IssueStateOpen   = "open"
+	IssueStateClosed = "closed"
+)
+
+type Issue struct {
+	Id        int64      `json:"id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	HtmlUrl   string     `json:"html_url"`
+	State     string     `json:"state"`
+	Labels    []Label    `json:"labels"`
+	Milestone *Milestone `json:"milestone"`
+}
+
+type Milestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+func (i *Issue) InDigest() bool {
+	return i.Milestone != nil
+}
+
 type createIssueRequest struct {
 	Title  string   `json:"title"`
 	Body   string   `json:"body"`
 	Labels []string `json:"labels"`
 }
 
-type issue struct {
+type createCommentRequest struct {
+	Body string `json:"body"`
+}
+
+type comment struct {
 	Id      int64  `json:"id"`
 	HtmlUrl string `json:"html_url"`
 }
 
-func wrapError(err error) error {
-	return fmt.Errorf("error occurred during CreateIssue call: %w", err)
+type searchIssuesResponse struct {
+	TotalCount int     `json:"total_count"`
+	Items      []Issue `json:"items"`
 }
 
-func NewClient(token string, githubRepo string) *Client {
+func wrapError(operation string, err error) error {
+	return fmt.Errorf("error occurred during %s call: %w", operation, err)
+}
+
+func NewClient(token string, githubRepo string, labelSettings LabelSettings, templates *IssueTemplates, httpClient *http.Client) *Client {
 	repoParts := strings.Split(githubRepo, "/")
 	owner := repoParts[0]
 	repo := repoParts[1]
@@ -50,67 +101,217 @@ func NewClient(token string, githubRepo string) *Client {
 		githubToken: token,
 		owner:       owner,
 		repo:        repo,
+		repoUrl:     fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo),
 		issuesUrl:   fmt.Sprintf("https://api.github.com/repos/%s/%s/issues", owner, repo),
+		searchUrl:   "https://api.github.com/search/issues",
+		labelsUrl:   fmt.Sprintf("https://api.github.com/repos/%s/%s/labels", owner, repo),
+		httpClient:  httpClient,
+		retryPolicy: retry.DefaultPolicy(),
+
+		labelSettings: labelSettings,
+		templates:     templates,
 	}
 }
 
-func (c *Client) CreateIssue(article *ArticleIssue) (string, error) {
-	payload := newCreateIssueRequest(article)
-	jsonPayload, err := json.Marshal(payload)
+func (c *Client) Repo() string {
+	return c.owner + "/" + c.repo
+}
+
+func (c *Client) CheckAccess(ctx context.Context) error {
+	var repository Repository
+	return c.call(ctx, "CheckAccess", "GET", c.repoUrl, nil, http.StatusOK, &repository)
+}
+
+func (c *Client) CreateIssue(ctx context.Context, article *ArticleIssue) (string, error) {
+	content, err := c.templates.RenderIssue(article)
 	if err != nil {
-		return "", wrapError(err)
+		return "", wrapError("CreateIssue", err)
 	}
 
-	req, err := http.NewRequest("POST", c.issuesUrl, bytes.NewBuffer(jsonPayload))
+	labels, err := c.resolveLabels(ctx, content.Labels)
 	if err != nil {
-		return "", wrapError(err)
+		return "", err
 	}
+	request := &createIssueRequest{Title: content.Title, Body: content.Body, Labels: labels}
 
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("Authorization", "Bearer "+c.githubToken)
-	req.Header.Add("Content-Type", "application/json")
+	var iss Issue
+	err = c.call(ctx, "CreateIssue", "POST", c.issuesUrl, request, http.StatusCreated, &iss)
+	if err != nil {
+		return "", err
+	}
+
+	return iss.HtmlUrl, nil
+}
+
+func (c *Client) FindIssueByUrl(ctx context.Context, articleUrl string) (*Issue, error) {
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("repo:%s/%s is:issue in:body \"%s\"", c.owner, c.repo, articleUrl))
 
-	res, err := http.DefaultClient.Do(req)
+	var res searchIssuesResponse
+	err := c.call(ctx, "FindIssueByUrl", "GET", c.searchUrl+"?"+query.Encode(), nil, http.StatusOK, &res)
 	if err != nil {
-		return "", wrapError(err)
+		return nil, err
 	}
 
-	defer res.Body.Close()
-	body, err := io.ReadAll(res.Body)
+	// The search is full-text, so make sure the match is the exact URL and not a similar one.
+	for _, iss := range res.Items {
+		if containsUrl(iss.Body, articleUrl) {
+			found := iss
+			return &found, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *Client) GetIssue(ctx context.Context, issueNumber int) (*Issue, error) {
+	var iss Issue
+	err := c.call(ctx, "GetIssue", "GET", fmt.Sprintf("%s/%d", c.issuesUrl, issueNumber), nil, http.StatusOK, &iss)
 	if err != nil {
-		return "", wrapError(err)
+		return nil, err
 	}
 
-	if res.StatusCode != 201 {
-		return "", fmt.Errorf("non-successful HTTP status code in CreateIssue call: %d", res.StatusCode)
+	return &iss, nil
+}
+
+func IssueNumber(issueUrl string) (int, bool) {
+	prefix, number, ok := strings.Cut(strings.TrimSuffix(issueUrl, "/"), "/issues/")
+	if !ok || prefix == "" {
+		return 0, false
 	}
 
-	var iss issue
-	err = json.Unmarshal(body, &iss)
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+func (c *Client) AddComment(ctx context.Context, issueNumber int, article *ArticleIssue) (string, error) {
+	commentsUrl := fmt.Sprintf("%s/%d/comments", c.issuesUrl, issueNumber)
+	body, err := c.templates.RenderComment(article)
 	if err != nil {
-		return "", wrapError(err)
+		return "", wrapError("AddComment", err)
 	}
 
-	return iss.HtmlUrl, nil
+	var com comment
+	err = c.call(ctx, "AddComment", "POST", commentsUrl, &createCommentRequest{Body: body}, http.StatusCreated, &com)
+	if err != nil {
+		return "", err
+	}
+
+	return com.HtmlUrl, nil
+}
+
+func containsUrl(text string, u string) bool {
+	for start := 0; ; {
+		i := strings.Index(text[start:], u)
+		if i < 0 {
+			return false
+		}
+		end := start + i + len(u)
+		if end == len(text) || !isUrlChar(text[end]) {
+			return true
+		}
+		start = end
+	}
+}
+
+func isUrlChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~:/?#[]@!$&'()*+,;=%", c) >= 0
+}
+
+func (c *Client) call(ctx context.Context, operation string, method string, requestUrl string, payload interface{}, expectedStatusCode int, result interface{}) error {
+	var jsonPayload []byte
+	if payload != nil {
+		var err error
+		jsonPayload, err = json.Marshal(payload)
+		if err != nil {
+			return wrapError(operation, err)
+		}
+	}
+
+	var resBody []byte
+	attempt := func() error {
+		var err error
+		resBody, err = c.send(ctx, operation, method, requestUrl, jsonPayload, expectedStatusCode)
+		return err
+	}
+
+	policy := c.retryPolicy
+	if method != http.MethodGet {
+		// A failed POST may still have been applied by GitHub, so the caller checks before repeating it.
+		policy = retry.Policy{MaxAttempts: 1}
+	}
+	err := policy.Do(ctx, attempt)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(resBody, result)
+	if err != nil {
+		return wrapError(operation, err)
+	}
+
+	return nil
 }
 
-func newCreateIssueRequest(article *ArticleIssue) *createIssueRequest {
-	title := article.Title
-	if len(article.Author) > 0 {
-		title = title + " / " + article.Author
+func (c *Client) send(ctx context.Context, operation string, method string, requestUrl string, jsonPayload []byte, expectedStatusCode int) ([]byte, error) {
+	resource := c.rateLimitResource(requestUrl)
+	if err := c.checkRateLimit(operation, resource, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if jsonPayload != nil {
+		body = bytes.NewReader(jsonPayload)
 	}
 
-	body := fmt.Sprintf("__URL:__ %s\n\n__Review (1-2 sentences):__ %s\n\n__Created by:__ DE or DIE Bot :robot: on behalf of %s.", article.Url, article.Description, article.User)
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl, body)
+	if err != nil {
+		return nil, wrapError(operation, err)
+	}
 
-	labels := make([]string, 0, len(article.Topics)+1)
-	labels = append(labels, "level:"+article.Level)
-	for _, topic := range article.Topics {
-		labels = append(labels, "topic:"+topic)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.githubToken)
+	if jsonPayload != nil {
+		req.Header.Add("Content-Type", "application/json")
 	}
 
-	return &createIssueRequest{
-		Title:  title,
-		Body:   body,
-		Labels: labels,
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		if retry.IsTransientError(ctx, err) {
+			return nil, &retry.RetryableError{Err: wrapError(operation, err)}
+		}
+		return nil, wrapError(operation, err)
 	}
+
+	defer res.Body.Close()
+	c.updateRateLimit(resource, res.Header, time.Now())
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		if retry.IsTransientError(ctx, err) {
+			return nil, &retry.RetryableError{Err: wrapError(operation, err)}
+		}
+		return nil, wrapError(operation, err)
+	}
+
+	if res.StatusCode != expectedStatusCode {
+		if err = rateLimitError(operation, res, time.Now()); err != nil {
+			return nil, err
+		}
+
+		err = newAPIError(operation, res.StatusCode, resBody)
+		if retry.IsTransientStatus(res.StatusCode) {
+			return nil, &retry.RetryableError{Err: err}
+		}
+		return nil, err
+	}
+
+	return resBody, nil
 }
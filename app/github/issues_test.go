@@ -1,18 +1,22 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/deordie/deordie-bot/app/retry"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateIssue_Success(t *testing.T) {
 	// Arrange
-	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	mockServer := httptest.NewServer(withLabels([]Label{{Name: "level:beginner"}, {Name: "topic:topic1"}, {Name: "topic:topic2"}}, func(w http.ResponseWriter, r *http.Request) {
 		// Verify request headers
 		assert.Equal(t, "Bearer FAKE_GITHUB_TOKEN", r.Header.Get("Authorization"), "unexpected Authorization header")
 		assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"), "unexpected Accept header")
@@ -39,9 +43,12 @@ func TestCreateIssue_Success(t *testing.T) {
 
 	client := &Client{
 		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
 		owner:       "owner",
 		repo:        "repo",
+		httpClient:  mockServer.Client(),
 		issuesUrl:   mockServer.URL,
+		labelsUrl:   mockServer.URL + "/labels",
 	}
 
 	article := &ArticleIssue{
@@ -55,7 +62,7 @@ func TestCreateIssue_Success(t *testing.T) {
 	}
 
 	// Act
-	url, err := client.CreateIssue(article)
+	url, err := client.CreateIssue(context.Background(), article)
 	assert.Nil(t, err, "unexpected error")
 
 	// Assert
@@ -64,16 +71,19 @@ func TestCreateIssue_Success(t *testing.T) {
 
 func TestCreateIssue_NonSuccessHttpStatus(t *testing.T) {
 	// Arrange
-	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	mockServer := httptest.NewServer(withLabels(nil, func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 	}))
 	defer mockServer.Close()
 
 	client := &Client{
 		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
 		owner:       "owner",
 		repo:        "repo",
+		httpClient:  mockServer.Client(),
 		issuesUrl:   mockServer.URL,
+		labelsUrl:   mockServer.URL + "/labels",
 	}
 
 	article := &ArticleIssue{
@@ -87,7 +97,7 @@ func TestCreateIssue_NonSuccessHttpStatus(t *testing.T) {
 	}
 
 	// Act
-	_, err := client.CreateIssue(article)
+	_, err := client.CreateIssue(context.Background(), article)
 
 	// Assert
 	assert.NotNil(t, err, "expected non-nil error")
@@ -96,7 +106,7 @@ func TestCreateIssue_NonSuccessHttpStatus(t *testing.T) {
 
 func TestCreateIssue_Failure(t *testing.T) {
 	// Arrange
-	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	mockServer := httptest.NewServer(withLabels(nil, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/vnd.github+json")
 		w.WriteHeader(http.StatusCreated)
 		_, _ = w.Write([]byte(`malformed JSON`))
@@ -105,9 +115,12 @@ func TestCreateIssue_Failure(t *testing.T) {
 
 	client := &Client{
 		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
 		owner:       "owner",
 		repo:        "repo",
+		httpClient:  mockServer.Client(),
 		issuesUrl:   mockServer.URL,
+		labelsUrl:   mockServer.URL + "/labels",
 	}
 
 	article := &ArticleIssue{
@@ -121,9 +134,503 @@ func TestCreateIssue_Failure(t *testing.T) {
 	}
 
 	// Act
-	_, err := client.CreateIssue(article)
+	_, err := client.CreateIssue(context.Background(), article)
 
 	// Assert
 	assert.NotNil(t, err, "expected non-nil error")
 	assert.EqualError(t, err, "error occurred during CreateIssue call: invalid character 'm' looking for beginning of value", "unexpected error message")
 }
+
+func TestFindIssueByUrl_Found(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "unexpected HTTP method")
+		assert.Equal(t, "Bearer FAKE_GITHUB_TOKEN", r.Header.Get("Authorization"), "unexpected Authorization header")
+		assert.Equal(t, "repo:owner/repo is:issue in:body \"https://example.com/article\"", r.URL.Query().Get("q"), "unexpected search query")
+
+		w.Header().Set("Content-Type", "application/vnd.github+json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"total_count": 2, "items": [
+			{"id": 1, "number": 10, "title": "Other", "body": "__URL:__ https://example.com/article-2", "html_url": "https://github.com/owner/repo/issues/10"},
+			{"id": 2, "number": 11, "title": "Sample Title", "body": "__URL:__ https://example.com/article\n\n__Review", "html_url": "https://github.com/owner/repo/issues/11"}
+		]}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		searchUrl:   mockServer.URL,
+	}
+
+	// Act
+	iss, err := client.FindIssueByUrl(context.Background(), "https://example.com/article")
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.NotNil(t, iss, "expected issue to be found")
+	assert.Equal(t, 11, iss.Number, "unexpected issue number")
+	assert.Equal(t, "https://github.com/owner/repo/issues/11", iss.HtmlUrl, "unexpected issue URL")
+}
+
+func TestFindIssueByUrl_NotFound(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.github+json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"total_count": 0, "items": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		searchUrl:   mockServer.URL,
+	}
+
+	// Act
+	iss, err := client.FindIssueByUrl(context.Background(), "https://example.com/article")
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Nil(t, iss, "expected no issue to be found")
+}
+
+func TestGetIssue_Success(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "unexpected HTTP method")
+		assert.Equal(t, "/12", r.URL.Path, "unexpected issue path")
+
+		w.Header().Set("Content-Type", "application/vnd.github+json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": 2, "number": 12, "title": "Sample Title", "state": "closed",
+			"html_url": "https://github.com/owner/repo/issues/12", "labels": [{"name": "level:beginner"}],
+			"milestone": {"number": 3, "title": "Digest #3"}}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+	}
+
+	// Act
+	iss, err := client.GetIssue(context.Background(), 12)
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, IssueStateClosed, iss.State, "unexpected issue state")
+	assert.Equal(t, []Label{{Name: "level:beginner"}}, iss.Labels, "unexpected issue labels")
+	assert.True(t, iss.InDigest(), "expected issue to be in digest")
+	assert.Equal(t, "Digest #3", iss.Milestone.Title, "unexpected milestone")
+}
+
+func TestGetIssue_NotFound(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+	}
+
+	// Act
+	iss, err := client.GetIssue(context.Background(), 12)
+
+	// Assert
+	assert.Nil(t, iss, "expected no issue")
+	assert.EqualError(t, err, "non-successful HTTP status code in GetIssue call: 404, Not Found", "unexpected error message")
+}
+
+func TestCheckAccess(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer FAKE_GITHUB_TOKEN", r.Header.Get("Authorization"), "unexpected Authorization header")
+
+		w.Header().Set("Content-Type", "application/vnd.github+json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": 1, "full_name": "owner/repo"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		repoUrl:     mockServer.URL,
+	}
+
+	// Act
+	err := client.CheckAccess(context.Background())
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+}
+
+func TestCheckAccess_BadCredentials(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message": "Bad credentials"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		repoUrl:     mockServer.URL,
+	}
+
+	// Act
+	err := client.CheckAccess(context.Background())
+
+	// Assert
+	assert.EqualError(t, err, "non-successful HTTP status code in CheckAccess call: 401, Bad credentials", "unexpected error message")
+}
+
+func TestIssueNumber(t *testing.T) {
+	testCases := []struct {
+		name     string
+		issueUrl string
+		expected int
+		ok       bool
+	}{
+		{"issue", "https://github.com/owner/repo/issues/12", 12, true},
+		{"trailing slash", "https://github.com/owner/repo/issues/12/", 12, true},
+		{"pull request", "https://github.com/owner/repo/pull/12", 0, false},
+		{"not a number", "https://github.com/owner/repo/issues/new", 0, false},
+		{"comment", "https://github.com/owner/repo/issues/12#issuecomment-1", 0, false},
+		{"empty", "", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual, ok := IssueNumber(tc.issueUrl)
+
+			// Assert
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestAddComment_Success(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/11/comments", r.URL.Path, "unexpected comments URL")
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"), "unexpected Content-Type header")
+
+		body, _ := io.ReadAll(r.Body)
+		defer r.Body.Close()
+
+		var req createCommentRequest
+		_ = json.Unmarshal(body, &req)
+		assert.Equal(t, "__Review (1-2 sentences):__ Sample description\n\n__Created by:__ DE or DIE Bot :robot: on behalf of user123.", req.Body)
+
+		w.Header().Set("Content-Type", "application/vnd.github+json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1, "html_url": "https://github.com/owner/repo/issues/11#issuecomment-1"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+	}
+
+	article := &ArticleIssue{
+		Url:         "https://example.com",
+		Description: "Sample description",
+		User:        "user123",
+	}
+
+	// Act
+	url, err := client.AddComment(context.Background(), 11, article)
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "https://github.com/owner/repo/issues/11#issuecomment-1", url, "unexpected comment URL")
+}
+
+func TestCreateIssue_Cancelled(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(withLabels(nil, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+		labelsUrl:   mockServer.URL + "/labels",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := client.CreateIssue(ctx, &ArticleIssue{Url: "https://example.com", Title: "Sample Title", Level: "beginner"})
+
+	// Assert
+	assert.NotNil(t, err, "expected non-nil error")
+	assert.ErrorIs(t, err, context.Canceled, "expected the cancellation to reach the HTTP call")
+}
+
+func TestCreateIssue_DoesNotRetryServerError(t *testing.T) {
+	// Arrange
+	attempts := 0
+	mockServer := httptest.NewServer(withLabels(nil, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+		labelsUrl:   mockServer.URL + "/labels",
+		retryPolicy: retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	}
+
+	// Act
+	_, err := client.CreateIssue(context.Background(), &ArticleIssue{Url: "https://example.com", Title: "Sample Title", Level: "beginner"})
+
+	// Assert
+	var temporaryErr *retry.TemporaryError
+	assert.ErrorAs(t, err, &temporaryErr)
+	assert.Equal(t, 1, attempts, "expected the issue creation not to be repeated")
+}
+
+func TestGetIssue_RetriesServerError(t *testing.T) {
+	// Arrange
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": 1, "number": 1, "html_url": "https://github.com/owner/repo/issues/1"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+		retryPolicy: retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	}
+
+	// Act
+	iss, err := client.GetIssue(context.Background(), 1)
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "https://github.com/owner/repo/issues/1", iss.HtmlUrl, "unexpected issue URL")
+	assert.Equal(t, 2, attempts, "expected a single retry")
+}
+
+func TestCreateIssue_RateLimitExceeded(t *testing.T) {
+	// Arrange
+	attempts := 0
+	resetAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	mockServer := httptest.NewServer(withLabels(nil, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+		labelsUrl:   mockServer.URL + "/labels",
+		retryPolicy: retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	}
+	article := &ArticleIssue{Url: "https://example.com", Title: "Sample Title", Level: "beginner"}
+
+	// Act
+	_, firstErr := client.CreateIssue(context.Background(), article)
+	_, secondErr := client.CreateIssue(context.Background(), article)
+
+	// Assert
+	var rateLimitErr *RateLimitError
+	assert.ErrorAs(t, firstErr, &rateLimitErr)
+	assert.True(t, resetAt.Equal(rateLimitErr.ResetAt), "unexpected reset time %s", rateLimitErr.ResetAt)
+	assert.ErrorAs(t, secondErr, &rateLimitErr)
+	assert.Equal(t, 1, attempts, "expected calls to fail fast until the rate limit resets")
+}
+
+func TestFindIssueByUrl_RateLimitDoesNotBlockOtherResources(t *testing.T) {
+	// Arrange
+	searches := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search" {
+			searches++
+			w.Header().Set("X-RateLimit-Resource", "search")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": 1, "number": 1, "html_url": "https://github.com/owner/repo/issues/1"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL + "/issues",
+		searchUrl:   mockServer.URL + "/search",
+	}
+
+	// Act
+	_, firstSearchErr := client.FindIssueByUrl(context.Background(), "https://example.com")
+	iss, getErr := client.GetIssue(context.Background(), 1)
+	_, secondSearchErr := client.FindIssueByUrl(context.Background(), "https://example.com")
+
+	// Assert
+	var rateLimitErr *RateLimitError
+	assert.ErrorAs(t, firstSearchErr, &rateLimitErr)
+	assert.Nil(t, getErr, "expected the search rate limit not to block the issue calls")
+	assert.Equal(t, 1, iss.Number)
+	assert.ErrorAs(t, secondSearchErr, &rateLimitErr)
+	assert.Equal(t, 1, searches, "expected searches to fail fast until the search rate limit resets")
+}
+
+func TestGetIssue_RetriesAfterRetryAfter(t *testing.T) {
+	// Arrange
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": 1, "number": 1, "html_url": "https://github.com/owner/repo/issues/1"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+		retryPolicy: retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	}
+
+	// Act
+	_, err := client.GetIssue(context.Background(), 1)
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, 2, attempts)
+}
+
+func TestCreateIssue_ValidationFailed(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(withLabels(nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{
+			"message": "Validation Failed",
+			"errors": [{"resource": "Label", "field": "name", "code": "invalid"}, "labels must be unique"],
+			"documentation_url": "https://docs.github.com/rest/issues/issues#create-an-issue"
+		}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		owner:       "owner",
+		repo:        "repo",
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL,
+		labelsUrl:   mockServer.URL + "/labels",
+	}
+
+	// Act
+	_, err := client.CreateIssue(context.Background(), &ArticleIssue{Url: "https://example.com", Title: "Sample Title", Level: "beginner"})
+
+	// Assert
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	expectedErr := &APIError{
+		Operation:        "CreateIssue",
+		StatusCode:       http.StatusUnprocessableEntity,
+		Message:          "Validation Failed",
+		DocumentationUrl: "https://docs.github.com/rest/issues/issues#create-an-issue",
+		Errors: []FieldError{
+			{Resource: "Label", Field: "name", Code: "invalid"},
+			{Message: "labels must be unique"},
+		},
+	}
+	assert.Equal(t, expectedErr, apiErr)
+	assert.Equal(t, "Validation Failed: name invalid; labels must be unique", apiErr.Reason())
+	assert.EqualError(t, err, "non-successful HTTP status code in CreateIssue call: 422, Validation Failed: name invalid; labels must be unique (see https://docs.github.com/rest/issues/issues#create-an-issue)", "unexpected error message")
+}
+
+// withLabels serves the labels of the repo and passes other calls to the handler.
+func withLabels(labels []Label, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/labels" {
+			handler(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(labels)
+	})
+}
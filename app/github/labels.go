@@ -0,0 +1,218 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	LevelLabelPrefix = "level:"
+	TopicLabelPrefix = "topic:"
+
+	labelsPerPage = 100
+)
+
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type LabelSettings struct {
+	CreateMissing bool
+	// Color of the created labels as six hex digits without "#".
+	Color string
+}
+
+func (c *Client) ListLabels(ctx context.Context) ([]Label, error) {
+	var labels []Label
+	for page := 1; ; page++ {
+		pageUrl := fmt.Sprintf("%s?per_page=%d&page=%d", c.labelsUrl, labelsPerPage, page)
+
+		var pageLabels []Label
+		err := c.call(ctx, "ListLabels", "GET", pageUrl, nil, http.StatusOK, &pageLabels)
+		if err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, pageLabels...)
+		if len(pageLabels) < labelsPerPage {
+			return labels, nil
+		}
+	}
+}
+
+func (c *Client) CreateLabel(ctx context.Context, name string) (*Label, error) {
+	var label Label
+	err := c.call(ctx, "CreateLabel", "POST", c.labelsUrl, &Label{Name: name, Color: c.labelSettings.Color}, http.StatusCreated, &label)
+	if err != nil {
+		return nil, err
+	}
+
+	return &label, nil
+}
+
+func (c *Client) resolveLabels(ctx context.Context, names []string) ([]string, error) {
+	existing, err := c.ListLabels(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to list GitHub labels, the labels are submitted as they are", "labels", names, "error", err)
+		return names, nil
+	}
+
+	resolved, missing := ResolveLabels(existing, names)
+	if len(missing) == 0 {
+		return resolved, nil
+	}
+	if !c.labelSettings.CreateMissing {
+		slog.WarnContext(ctx, "Labels don't exist in the repo and are dropped", "labels", missing)
+		return resolved, nil
+	}
+
+	for _, name := range missing {
+		label, err := c.CreateLabel(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, *label)
+	}
+	resolved, _ = ResolveLabels(existing, names)
+	return resolved, nil
+}
+
+func ResolveLabels(existing []Label, names []string) ([]string, []string) {
+	resolved := make([]string, 0, len(names))
+	var missing []string
+	var missingLabels []Label
+	seen := make(map[string]bool)
+	for _, name := range names {
+		prefix, value := splitLabel(name)
+		match, ok := MatchLabel(existing, prefix, value)
+		if !ok {
+			if _, ok := MatchLabel(missingLabels, prefix, value); !ok {
+				missing = append(missing, name)
+				missingLabels = append(missingLabels, Label{Name: name})
+			}
+			continue
+		}
+
+		name = prefix + match
+		if !seen[name] {
+			seen[name] = true
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved, missing
+}
+
+func MatchLabel(labels []Label, prefix string, value string) (string, bool) {
+	normalized := normalizeLabel(value)
+	for _, label := range labels {
+		labelValue, ok := cutPrefixFold(label.Name, prefix)
+		if ok && normalizeLabel(labelValue) == normalized {
+			return labelValue, true
+		}
+	}
+	return "", false
+}
+
+func ClosestLabels(labels []Label, prefix string, value string, limit int) []string {
+	type candidate struct {
+		value    string
+		distance int
+	}
+
+	normalized := normalizeLabel(value)
+	maxDistance := max(2, utf8.RuneCountInString(normalized)/3)
+
+	var candidates []candidate
+	for _, label := range labels {
+		labelValue, ok := cutPrefixFold(label.Name, prefix)
+		if !ok {
+			continue
+		}
+
+		normalizedLabel := normalizeLabel(labelValue)
+		distance := levenshtein(normalized, normalizedLabel)
+		if distance <= maxDistance || (len(normalized) >= 3 && strings.Contains(normalizedLabel, normalized)) {
+			candidates = append(candidates, candidate{value: labelValue, distance: distance})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	closest := make([]string, 0, min(limit, len(candidates)))
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		closest = append(closest, candidates[i].value)
+	}
+	return closest
+}
+
+func splitLabel(name string) (string, string) {
+	if i := strings.Index(name, ":"); i >= 0 {
+		return name[:i+1], name[i+1:]
+	}
+	return "", name
+}
+
+func cutPrefixFold(name string, prefix string) (string, bool) {
+	if len(name) < len(prefix) || !strings.EqualFold(name[:len(prefix)], prefix) {
+		return "", false
+	}
+	return name[len(prefix):], true
+}
+
+func normalizeLabel(value string) string {
+	words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return r == '-' || r == '_' || r == ' ' || r == '.' || r == '/'
+	})
+
+	for i, word := range words {
+		words[i] = singular(word)
+	}
+	return strings.Join(words, "")
+}
+
+func singular(word string) string {
+	switch {
+	case len(word) <= 3:
+		return word
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,214 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var repoLabels = []Label{
+	{Name: "level:beginner"},
+	{Name: "level:advanced"},
+	{Name: "topic:storage-engine"},
+	{Name: "topic:Streaming"},
+	{Name: "topic:kafka"},
+	{Name: "topic:databases"},
+	{Name: "bug"},
+}
+
+func TestMatchLabel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected string
+		ok       bool
+	}{
+		{"exact", "kafka", "kafka", true},
+		{"case", "streaming", "Streaming", true},
+		{"separators and plural", "Storage Engines", "storage-engine", true},
+		{"singular of plural label", "database", "databases", true},
+		{"unknown", "spark", "", false},
+		{"other prefix", "beginner", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual, ok := MatchLabel(repoLabels, TopicLabelPrefix, tc.value)
+
+			// Assert
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestClosestLabels(t *testing.T) {
+	// Act
+	misspelled := ClosestLabels(repoLabels, TopicLabelPrefix, "straming", 3)
+	partial := ClosestLabels(repoLabels, TopicLabelPrefix, "storage", 3)
+	unrelated := ClosestLabels(repoLabels, TopicLabelPrefix, "machine-learning", 3)
+
+	// Assert
+	assert.Equal(t, []string{"Streaming"}, misspelled)
+	assert.Equal(t, []string{"storage-engine"}, partial)
+	assert.Empty(t, unrelated)
+}
+
+func TestResolveLabels(t *testing.T) {
+	// Act
+	resolved, missing := ResolveLabels(repoLabels, []string{"level:advanced", "topic:Storage Engines", "topic:spark", "topic:Spark", "topic:storage-engine"})
+
+	// Assert
+	assert.Equal(t, []string{"level:advanced", "topic:storage-engine"}, resolved)
+	assert.Equal(t, []string{"topic:spark"}, missing)
+}
+
+func TestListLabels_Paginated(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "100", r.URL.Query().Get("per_page"), "unexpected page size")
+
+		labels := make([]Label, 0, labelsPerPage)
+		if r.URL.Query().Get("page") == "1" {
+			for i := 0; i < labelsPerPage; i++ {
+				labels = append(labels, Label{Name: fmt.Sprintf("topic:topic%d", i)})
+			}
+		} else {
+			labels = append(labels, Label{Name: "level:beginner"})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(labels)
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		httpClient:  mockServer.Client(),
+		labelsUrl:   mockServer.URL,
+	}
+
+	// Act
+	labels, err := client.ListLabels(context.Background())
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Len(t, labels, labelsPerPage+1)
+	assert.Equal(t, "level:beginner", labels[labelsPerPage].Name)
+}
+
+func TestCreateIssue_MatchesAndDropsLabels(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(withLabels(repoLabels, func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEqual(t, "/labels", r.URL.Path, "unexpected label creation")
+
+		body, _ := io.ReadAll(r.Body)
+		var req createIssueRequest
+		_ = json.Unmarshal(body, &req)
+		assert.Equal(t, []string{"level:advanced", "topic:Streaming", "topic:storage-engine"}, req.Labels)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1, "html_url": "https://github.com/owner/repo/issues/1"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL + "/issues",
+		labelsUrl:   mockServer.URL + "/labels",
+	}
+
+	// Act
+	_, err := client.CreateIssue(context.Background(), &ArticleIssue{
+		Url:    "https://example.com",
+		Title:  "Sample Title",
+		Level:  "Advanced",
+		Topics: []string{"streaming", "storage engines", "Streaming", "spark"},
+	})
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+}
+
+func TestCreateIssue_WhenLabelsCannotBeListed(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/labels" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		var req createIssueRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, []string{"level:beginner", "topic:spark"}, req.Labels)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1, "html_url": "https://github.com/owner/repo/issues/1"}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken: "FAKE_GITHUB_TOKEN",
+		templates:   DefaultIssueTemplates(),
+		httpClient:  mockServer.Client(),
+		issuesUrl:   mockServer.URL + "/issues",
+		labelsUrl:   mockServer.URL + "/labels",
+	}
+
+	// Act
+	url, err := client.CreateIssue(context.Background(), &ArticleIssue{Url: "https://example.com", Title: "Sample Title", Level: "beginner", Topics: []string{"spark"}})
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "https://github.com/owner/repo/issues/1", url)
+}
+
+func TestCreateIssue_CreatesMissingLabels(t *testing.T) {
+	// Arrange
+	var created []Label
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/labels" && r.Method == "GET":
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(repoLabels)
+		case r.URL.Path == "/labels" && r.Method == "POST":
+			var label Label
+			_ = json.NewDecoder(r.Body).Decode(&label)
+			created = append(created, label)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(label)
+		default:
+			var req createIssueRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, []string{"level:beginner", "topic:spark"}, req.Labels)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1, "html_url": "https://github.com/owner/repo/issues/1"}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		githubToken:   "FAKE_GITHUB_TOKEN",
+		templates:     DefaultIssueTemplates(),
+		httpClient:    mockServer.Client(),
+		issuesUrl:     mockServer.URL + "/issues",
+		labelsUrl:     mockServer.URL + "/labels",
+		labelSettings: LabelSettings{CreateMissing: true, Color: "1d76db"},
+	}
+
+	// Act
+	_, err := client.CreateIssue(context.Background(), &ArticleIssue{Url: "https://example.com", Title: "Sample Title", Level: "beginner", Topics: []string{"spark"}})
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, []Label{{Name: "topic:spark", Color: "1d76db"}}, created)
+}
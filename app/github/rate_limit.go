@@ -0,0 +1,95 @@
+package github
+
+import (
+	"fmt"
+	"github.com/deordie/deordie-bot/app/retry"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	rateLimitResourceCore   = "core"
+	rateLimitResourceSearch = "search"
+)
+
+type RateLimitError struct {
+	Operation string
+	ResetAt   time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.ResetAt.IsZero() {
+		return fmt.Sprintf("GitHub rate limit exceeded in %s call", e.Operation)
+	}
+	return fmt.Sprintf("GitHub rate limit exceeded in %s call, resets at %s", e.Operation, e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+func (c *Client) rateLimitResource(requestUrl string) string {
+	if c.searchUrl != "" && strings.HasPrefix(requestUrl, c.searchUrl) {
+		return rateLimitResourceSearch
+	}
+	return rateLimitResourceCore
+}
+
+func (c *Client) checkRateLimit(operation string, resource string, now time.Time) error {
+	c.rateLimitMutex.Lock()
+	defer c.rateLimitMutex.Unlock()
+
+	if resetAt := c.rateLimitResetAt[resource]; now.Before(resetAt) {
+		return &RateLimitError{Operation: operation, ResetAt: resetAt}
+	}
+	return nil
+}
+
+func (c *Client) updateRateLimit(resource string, header http.Header, now time.Time) {
+	resetAt := rateLimitResetAt(header, now)
+	if resetAt.IsZero() {
+		return
+	}
+	if reported := header.Get("X-RateLimit-Resource"); reported != "" {
+		resource = reported
+	}
+
+	c.rateLimitMutex.Lock()
+	defer c.rateLimitMutex.Unlock()
+
+	if c.rateLimitResetAt == nil {
+		c.rateLimitResetAt = make(map[string]time.Time)
+	}
+	if resetAt.After(c.rateLimitResetAt[resource]) {
+		c.rateLimitResetAt[resource] = resetAt
+	}
+}
+
+func rateLimitError(operation string, res *http.Response, now time.Time) error {
+	resetAt := rateLimitResetAt(res.Header, now)
+	if res.StatusCode != http.StatusTooManyRequests && (res.StatusCode != http.StatusForbidden || resetAt.IsZero()) {
+		return nil
+	}
+
+	var wait time.Duration
+	if !resetAt.IsZero() {
+		wait = resetAt.Sub(now)
+	}
+	return &retry.RetryableError{Err: &RateLimitError{Operation: operation, ResetAt: resetAt}, After: wait}
+}
+
+func rateLimitResetAt(header http.Header, now time.Time) time.Time {
+	if retryAfter := retry.ParseRetryAfter(header.Get("Retry-After"), now); retryAfter > 0 {
+		return now.Add(retryAfter)
+	}
+
+	if header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	if resetAt := time.Unix(reset, 0); resetAt.After(now) {
+		return resetAt
+	}
+	return time.Time{}
+}
@@ -0,0 +1,107 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const (
+	DefaultIssueTitleTemplate   = "{{.Title}}{{with .Author}} / {{.}}{{end}}"
+	DefaultIssueBodyTemplate    = "__URL:__ {{.Url}}\n\n__Review (1-2 sentences):__ {{.Description}}\n\n__Created by:__ DE or DIE Bot :robot: on behalf of {{.User}}."
+	DefaultIssueCommentTemplate = "__Review (1-2 sentences):__ {{.Description}}\n\n__Created by:__ DE or DIE Bot :robot: on behalf of {{.User}}."
+)
+
+type IssueTemplates struct {
+	title   *template.Template
+	body    *template.Template
+	comment *template.Template
+}
+
+type IssueContent struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+func NewIssueTemplates(title string, body string, comment string) (*IssueTemplates, error) {
+	var err error
+	templates := &IssueTemplates{}
+	if templates.title, err = parseTemplate("title", title, DefaultIssueTitleTemplate); err != nil {
+		return nil, err
+	}
+	if templates.body, err = parseTemplate("body", body, DefaultIssueBodyTemplate); err != nil {
+		return nil, err
+	}
+	if templates.comment, err = parseTemplate("comment", comment, DefaultIssueCommentTemplate); err != nil {
+		return nil, err
+	}
+
+	sample := &ArticleIssue{Url: "https://example.com", Title: "Title", Author: "Author", Description: "Description", Level: "level", Topics: []string{"topic"}, User: "https://t.me/user"}
+	content, err := templates.RenderIssue(sample)
+	if err != nil {
+		return nil, err
+	}
+	if content.Title == "" {
+		return nil, fmt.Errorf("issue title template renders an empty title")
+	}
+	if _, err = templates.RenderComment(sample); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func DefaultIssueTemplates() *IssueTemplates {
+	templates, err := NewIssueTemplates("", "", "")
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+func (t *IssueTemplates) RenderIssue(article *ArticleIssue) (*IssueContent, error) {
+	title, err := execute(t.title, article)
+	if err != nil {
+		return nil, err
+	}
+	body, err := execute(t.body, article)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, 0, len(article.Topics)+1)
+	labels = append(labels, LevelLabelPrefix+article.Level)
+	for _, topic := range article.Topics {
+		labels = append(labels, TopicLabelPrefix+topic)
+	}
+
+	return &IssueContent{
+		Title:  title,
+		Body:   body,
+		Labels: labels,
+	}, nil
+}
+
+func (t *IssueTemplates) RenderComment(article *ArticleIssue) (string, error) {
+	return execute(t.comment, article)
+}
+
+func parseTemplate(name string, text string, defaultText string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = defaultText
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issue %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func execute(tmpl *template.Template, article *ArticleIssue) (string, error) {
+	var text strings.Builder
+	if err := tmpl.Execute(&text, article); err != nil {
+		return "", fmt.Errorf("invalid issue %s template: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(text.String()), nil
+}
@@ -0,0 +1,63 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderIssue_DefaultTemplates(t *testing.T) {
+	// Arrange
+	templates := DefaultIssueTemplates()
+
+	// Act
+	content, err := templates.RenderIssue(&ArticleIssue{Url: "https://example.com", Title: "Sample Title", Author: "Author", Description: "Nice article.", Level: "beginner", Topics: []string{"kafka"}, User: "https://t.me/user"})
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Sample Title / Author", content.Title)
+	assert.Equal(t, "__URL:__ https://example.com\n\n__Review (1-2 sentences):__ Nice article.\n\n__Created by:__ DE or DIE Bot :robot: on behalf of https://t.me/user.", content.Body)
+	assert.Equal(t, []string{"level:beginner", "topic:kafka"}, content.Labels)
+}
+
+func TestRenderIssue_CustomTemplates(t *testing.T) {
+	// Arrange
+	templates, err := NewIssueTemplates("[{{.Level}}] {{.Title}}", "{{.Url}}\nTopics: {{join .Topics \", \"}}", "+1 from {{.User}}")
+	assert.Nil(t, err, "unexpected error")
+	article := &ArticleIssue{Url: "https://example.com", Title: "Sample Title", Level: "advanced", Topics: []string{"kafka", "streaming"}, User: "https://t.me/user"}
+
+	// Act
+	content, issueErr := templates.RenderIssue(article)
+	comment, commentErr := templates.RenderComment(article)
+
+	// Assert
+	assert.Nil(t, issueErr, "unexpected error")
+	assert.Nil(t, commentErr, "unexpected error")
+	assert.Equal(t, "[advanced] Sample Title", content.Title)
+	assert.Equal(t, "https://example.com\nTopics: kafka, streaming", content.Body)
+	assert.Equal(t, "+1 from https://t.me/user", comment)
+}
+
+func TestNewIssueTemplates_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		title   string
+		body    string
+		comment string
+		err     string
+	}{
+		{"syntax", "{{.Title", "", "", "invalid issue title template"},
+		{"unknown field", "", "{{.Summary}}", "", "invalid issue body template"},
+		{"empty title", "{{if false}}{{end}}", "", "", "issue title template renders an empty title"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := NewIssueTemplates(tc.title, tc.body, tc.comment)
+
+			// Assert
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/deordie/deordie-bot/app/logging"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	SignatureHeader = "X-Hub-Signature-256"
+	EventHeader     = "X-GitHub-Event"
+	DeliveryHeader  = "X-GitHub-Delivery"
+
+	IssueActionClosed     = "closed"
+	IssueActionLabeled    = "labeled"
+	IssueActionMilestoned = "milestoned"
+
+	maxWebhookPayloadSize = 1 << 20
+)
+
+type IssuesEvent struct {
+	Action     string     `json:"action"`
+	Issue      Issue      `json:"issue"`
+	Label      *Label     `json:"label"`
+	Milestone  *Milestone `json:"milestone"`
+	Repository Repository `json:"repository"`
+}
+
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+type WebhookHandler struct {
+	secret  []byte
+	repo    string
+	onIssue func(ctx context.Context, event *IssuesEvent)
+}
+
+func NewWebhookHandler(secret string, githubRepo string, onIssue func(ctx context.Context, event *IssuesEvent)) *WebhookHandler {
+	return &WebhookHandler{
+		secret:  []byte(secret),
+		repo:    githubRepo,
+		onIssue: onIssue,
+	}
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := logging.WithCorrelationId(r.Context(), r.Header.Get(DeliveryHeader))
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayloadSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !ValidSignature(h.secret, body, r.Header.Get(SignatureHeader)) {
+		slog.WarnContext(ctx, "Rejected GitHub webhook event with an invalid signature", "remote_addr", r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// Other events, e.g. the "ping" sent when the webhook is created, are acknowledged and ignored.
+	if r.Header.Get(EventHeader) != "issues" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var event IssuesEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		slog.WarnContext(ctx, "Cannot parse GitHub issues event", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Issue numbers are only unique within a repo.
+	if !strings.EqualFold(event.Repository.FullName, h.repo) {
+		slog.InfoContext(ctx, "Ignored GitHub issues event of another repo", "repo", event.Repository.FullName)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.onIssue(ctx, &event)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func ValidSignature(secret []byte, body []byte, signature string) bool {
+	hexSignature, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+
+	actual, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(actual, mac.Sum(nil))
+}
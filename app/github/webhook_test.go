@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const issuesEventPayload = `{"action": "milestoned", "issue": {"number": 12, "title": "Sample Title", "state": "open",
+	"html_url": "https://github.com/owner/repo/issues/12", "milestone": {"number": 3, "title": "#3"}},
+	"milestone": {"number": 3, "title": "#3"}, "repository": {"full_name": "Owner/Repo"}}`
+
+func sign(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookRequest(event string, body string, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/github/webhook", strings.NewReader(body))
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(SignatureHeader, signature)
+	return req
+}
+
+func TestWebhookHandler_IssuesEvent(t *testing.T) {
+	// Arrange
+	var received *IssuesEvent
+	handler := NewWebhookHandler("secret", "owner/repo", func(_ context.Context, event *IssuesEvent) { received = event })
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, newWebhookRequest("issues", issuesEventPayload, sign("secret", issuesEventPayload)))
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.NotNil(t, received, "expected the event to be passed on")
+	assert.Equal(t, IssueActionMilestoned, received.Action)
+	assert.Equal(t, 12, received.Issue.Number)
+	assert.Equal(t, "#3", received.Milestone.Title)
+}
+
+func TestWebhookHandler_InvalidSignature(t *testing.T) {
+	testCases := []struct {
+		name      string
+		signature string
+	}{
+		{"other secret", sign("other", issuesEventPayload)},
+		{"sha1", "sha1=" + strings.Repeat("0", 40)},
+		{"not hex", "sha256=zz"},
+		{"missing", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			handler := NewWebhookHandler("secret", "owner/repo", func(_ context.Context, event *IssuesEvent) { called = true })
+			recorder := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(recorder, newWebhookRequest("issues", issuesEventPayload, tc.signature))
+
+			// Assert
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			assert.False(t, called, "expected the event to be rejected")
+		})
+	}
+}
+
+func TestWebhookHandler_IgnoredEvents(t *testing.T) {
+	testCases := []struct {
+		name  string
+		event string
+		body  string
+	}{
+		{"ping", "ping", `{"zen": "Keep it logically awesome."}`},
+		{"other event", "issue_comment", issuesEventPayload},
+		{"other repo", "issues", strings.Replace(issuesEventPayload, "Owner/Repo", "owner/other", 1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			handler := NewWebhookHandler("secret", "owner/repo", func(_ context.Context, event *IssuesEvent) { called = true })
+			recorder := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(recorder, newWebhookRequest(tc.event, tc.body, sign("secret", tc.body)))
+
+			// Assert
+			assert.Equal(t, http.StatusNoContent, recorder.Code)
+			assert.False(t, called, "expected the event to be ignored")
+		})
+	}
+}
+
+func TestWebhookHandler_MalformedPayload(t *testing.T) {
+	// Arrange
+	handler := NewWebhookHandler("secret", "owner/repo", func(_ context.Context, event *IssuesEvent) {})
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, newWebhookRequest("issues", "malformed JSON", sign("secret", "malformed JSON")))
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestWebhookHandler_TooLargePayload(t *testing.T) {
+	// Arrange
+	body := strings.Repeat(" ", maxWebhookPayloadSize+1)
+	handler := NewWebhookHandler("secret", "owner/repo", func(_ context.Context, event *IssuesEvent) {})
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, newWebhookRequest("issues", body, sign("secret", body)))
+
+	// Assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+}
+
+func TestWebhookHandler_WrongMethod(t *testing.T) {
+	// Arrange
+	handler := NewWebhookHandler("secret", "owner/repo", func(_ context.Context, event *IssuesEvent) {})
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/github/webhook", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type Probe struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type Readiness struct {
+	probes  []Probe
+	ttl     time.Duration
+	timeout time.Duration
+
+	mutex     sync.Mutex
+	results   map[string]error
+	checkedAt time.Time
+}
+
+func NewReadiness(ttl time.Duration, timeout time.Duration, probes ...Probe) *Readiness {
+	return &Readiness{
+		probes:  probes,
+		ttl:     ttl,
+		timeout: timeout,
+	}
+}
+
+func (r *Readiness) Check() map[string]error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.results != nil && time.Since(r.checkedAt) < r.ttl {
+		return r.results
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	errs := make([]error, len(r.probes))
+	var wg sync.WaitGroup
+	for i, probe := range r.probes {
+		wg.Add(1)
+		go func(i int, probe Probe) {
+			defer wg.Done()
+			errs[i] = runProbe(ctx, probe)
+		}(i, probe)
+	}
+	wg.Wait()
+
+	results := make(map[string]error)
+	for i, probe := range r.probes {
+		if errs[i] != nil {
+			results[probe.Name] = errs[i]
+		}
+	}
+	r.results = results
+	r.checkedAt = time.Now()
+	return results
+}
+
+// runProbe gives up on the probe once the context is done, even if the probe itself ignores the context.
+func runProbe(ctx context.Context, probe Probe) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- probe.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("probe timed out: %w", ctx.Err())
+	}
+}
+
+type readinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	results := r.Check()
+
+	response := readinessResponse{Status: "ready", Checks: make(map[string]string)}
+	for _, probe := range r.probes {
+		response.Checks[probe.Name] = "ok"
+	}
+	for name, err := range results {
+		response.Status = "not ready"
+		response.Checks[name] = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(results) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func Liveness(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
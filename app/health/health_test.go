@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadiness_CachesResults(t *testing.T) {
+	// Arrange
+	calls := 0
+	probe := Probe{Name: "github", Check: func(context.Context) error {
+		calls++
+		return nil
+	}}
+	readiness := NewReadiness(time.Hour, time.Second, probe)
+
+	// Act
+	first := readiness.Check()
+	second := readiness.Check()
+
+	// Assert
+	assert.Empty(t, first)
+	assert.Empty(t, second)
+	assert.Equal(t, 1, calls, "expected the second check to use the cached result")
+}
+
+func TestReadiness_RefreshesExpiredResults(t *testing.T) {
+	// Arrange
+	calls := 0
+	probe := Probe{Name: "github", Check: func(context.Context) error {
+		calls++
+		return nil
+	}}
+	readiness := NewReadiness(time.Nanosecond, time.Second, probe)
+
+	// Act
+	readiness.Check()
+	time.Sleep(time.Millisecond)
+	readiness.Check()
+
+	// Assert
+	assert.Equal(t, 2, calls)
+}
+
+func TestReadiness_TimesOutHungProbe(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	defer close(release)
+	probe := Probe{Name: "telegram", Check: func(context.Context) error {
+		<-release
+		return nil
+	}}
+	readiness := NewReadiness(time.Hour, 10*time.Millisecond, probe)
+
+	// Act
+	results := readiness.Check()
+
+	// Assert
+	assert.ErrorIs(t, results["telegram"], context.DeadlineExceeded)
+}
+
+func TestReadiness_ServeHTTP(t *testing.T) {
+	// Arrange
+	readiness := NewReadiness(time.Hour, time.Second,
+		Probe{Name: "telegram", Check: func(context.Context) error { return nil }},
+		Probe{Name: "github", Check: func(context.Context) error { return errors.New("bad credentials") }},
+	)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	readiness.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.JSONEq(t, `{"status": "not ready", "checks": {"telegram": "ok", "github": "bad credentials"}}`, recorder.Body.String())
+}
+
+func TestReadiness_ServeHTTP_WhenReady(t *testing.T) {
+	// Arrange
+	readiness := NewReadiness(time.Hour, time.Second, Probe{Name: "telegram", Check: func(context.Context) error { return nil }})
+	recorder := httptest.NewRecorder()
+
+	// Act
+	readiness.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status": "ready", "checks": {"telegram": "ok"}}`, recorder.Body.String())
+}
+
+func TestReadiness_ServeHTTP_WhenRequestCancelled(t *testing.T) {
+	// Arrange
+	readiness := NewReadiness(time.Hour, time.Second, Probe{Name: "github", Check: func(ctx context.Context) error { return ctx.Err() }})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx)
+
+	// Act
+	readiness.ServeHTTP(httptest.NewRecorder(), req)
+	results := readiness.Check()
+
+	// Assert
+	assert.Empty(t, results, "expected the cancelled request not to fail the probes")
+}
+
+func TestLiveness(t *testing.T) {
+	// Arrange
+	recorder := httptest.NewRecorder()
+
+	// Act
+	Liveness(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ok\n", recorder.Body.String())
+}
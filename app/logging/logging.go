@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatText = "text"
+	FormatJson = "json"
+
+	CorrelationIdKey = "correlation_id"
+
+	redacted = "[REDACTED]"
+)
+
+var sensitiveKeys = []string{"authorization", "token", "secret", "password", "api_key", "apikey"}
+
+type Settings struct {
+	Format  string
+	Level   slog.Level
+	Secrets []string
+}
+
+func New(w io.Writer, settings Settings) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: settings.Level, ReplaceAttr: newRedactor(settings.Secrets)}
+
+	var handler slog.Handler
+	if settings.Format == FormatJson {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	return slog.New(&correlationHandler{Handler: handler})
+}
+
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, err
+	}
+	return level, nil
+}
+
+type correlationIdContextKey struct{}
+
+func NewCorrelationId() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		panic(fmt.Sprintf("can't generate correlation ID: %s", err.Error()))
+	}
+	return hex.EncodeToString(id)
+}
+
+func WithCorrelationId(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationIdContextKey{}, id)
+}
+
+func CorrelationId(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIdContextKey{}).(string)
+	return id
+}
+
+type correlationHandler struct {
+	slog.Handler
+}
+
+func (h *correlationHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := CorrelationId(ctx); id != "" {
+		record.AddAttrs(slog.String(CorrelationIdKey, id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *correlationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &correlationHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *correlationHandler) WithGroup(name string) slog.Handler {
+	return &correlationHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+func newRedactor(secrets []string) func(groups []string, attr slog.Attr) slog.Attr {
+	var pairs []string
+	for _, secret := range secrets {
+		if secret != "" {
+			pairs = append(pairs, secret, redacted)
+		}
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	return func(_ []string, attr slog.Attr) slog.Attr {
+		if isSensitiveKey(attr.Key) {
+			return slog.String(attr.Key, redacted)
+		}
+		if len(pairs) == 0 {
+			return attr
+		}
+
+		switch attr.Value.Kind() {
+		case slog.KindString:
+			return slog.String(attr.Key, replacer.Replace(attr.Value.String()))
+		case slog.KindAny:
+			switch value := attr.Value.Any().(type) {
+			case error:
+				return slog.String(attr.Key, replacer.Replace(value.Error()))
+			case fmt.Stringer:
+				return slog.String(attr.Key, replacer.Replace(value.String()))
+			}
+		}
+		return attr
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_AddsCorrelationId(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	logger := New(&out, Settings{Format: FormatJson, Level: slog.LevelInfo})
+	ctx := WithCorrelationId(context.Background(), "0123456789abcdef")
+
+	// Act
+	logger.InfoContext(ctx, "Article extracted", "url", "https://example.com")
+
+	// Assert
+	var record map[string]any
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &record), "expected a JSON record")
+	assert.Equal(t, "Article extracted", record["msg"])
+	assert.Equal(t, "https://example.com", record["url"])
+	assert.Equal(t, "0123456789abcdef", record[CorrelationIdKey])
+}
+
+func TestNew_FiltersByLevel(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	logger := New(&out, Settings{Format: FormatText, Level: slog.LevelWarn})
+
+	// Act
+	logger.Info("Article extracted")
+	logger.Warn("Label is dropped")
+
+	// Assert
+	assert.NotContains(t, out.String(), "Article extracted")
+	assert.Contains(t, out.String(), "level=WARN msg=\"Label is dropped\"")
+}
+
+func TestNew_RedactsSecrets(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	logger := New(&out, Settings{Format: FormatText, Level: slog.LevelInfo, Secrets: []string{"123:bot-token", ""}})
+
+	// Act
+	logger.Error("Failed to call https://api.telegram.org/bot123:bot-token/getMe",
+		"error", errors.New(`Post "https://api.telegram.org/bot123:bot-token/getMe": EOF`),
+		"Authorization", "Bearer ghp_secret",
+		"github_token", "ghp_secret")
+
+	// Assert
+	assert.NotContains(t, out.String(), "bot-token")
+	assert.NotContains(t, out.String(), "ghp_secret")
+	assert.Contains(t, out.String(), "https://api.telegram.org/bot[REDACTED]/getMe")
+	assert.Contains(t, out.String(), "Authorization=[REDACTED]")
+}
+
+func TestParseLevel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected slog.Level
+		ok       bool
+	}{
+		{"lower case", "debug", slog.LevelDebug, true},
+		{"upper case", "WARN", slog.LevelWarn, true},
+		{"unknown", "verbose", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			level, err := ParseLevel(tc.value)
+
+			// Assert
+			assert.Equal(t, tc.ok, err == nil)
+			assert.Equal(t, tc.expected, level)
+		})
+	}
+}
+
+func TestTransport_LogsWithoutHeaders(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer mockServer.Close()
+
+	var out bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(New(&out, Settings{Format: FormatText, Level: slog.LevelDebug}))
+	defer slog.SetDefault(previous)
+
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(WithCorrelationId(context.Background(), "0123456789abcdef"), "POST", mockServer.URL+"/issues?token=abc", nil)
+	req.Header.Set("Authorization", "Bearer ghp_secret")
+
+	// Act
+	res, err := client.Do(req)
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	_ = res.Body.Close()
+	assert.Contains(t, out.String(), "msg=\"Outbound HTTP request\" method=POST")
+	assert.Contains(t, out.String(), "path=/issues")
+	assert.Contains(t, out.String(), "status=201")
+	assert.Contains(t, out.String(), "correlation_id=0123456789abcdef")
+	assert.NotContains(t, out.String(), "ghp_secret")
+	assert.NotContains(t, out.String(), "token=abc")
+}
@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Transport struct {
+	next http.RoundTripper
+}
+
+func NewTransport(next http.RoundTripper) *Transport {
+	return &Transport{next: next}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+
+	attrs := []any{"method", req.Method, "host", req.URL.Host, "path", req.URL.Path, "duration", time.Since(start)}
+	if err != nil {
+		slog.DebugContext(req.Context(), "Outbound HTTP request failed", append(attrs, "error", err)...)
+		return nil, err
+	}
+	slog.DebugContext(req.Context(), "Outbound HTTP request", append(attrs, "status", res.StatusCode)...)
+	return res, nil
+}
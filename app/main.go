@@ -1,24 +1,257 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/canonical"
+	"github.com/deordie/deordie-bot/app/extractor"
 	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/logging"
 	"github.com/deordie/deordie-bot/app/rapidapi"
+	"github.com/deordie/deordie-bot/app/storage"
 	"github.com/deordie/deordie-bot/app/telegram"
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 )
 
 func main() {
+	if err := run(); err != nil {
+		slog.Error("The bot failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
 	env, err := LoadEnvironment()
 	if err != nil {
-		log.Fatalf("Can't read environment: %s", err.Error())
+		return fmt.Errorf("can't read environment: %w", err)
+	}
+	slog.SetDefault(newLogger(env))
+
+	config, err := LoadConfig(env.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("can't read config: %w", err)
+	}
+
+	stateStorage, err := newStateStorage(env)
+	if err != nil {
+		return fmt.Errorf("can't open state storage: %w", err)
+	}
+	defer stateStorage.Close()
+
+	outbox, err := newOutbox(env)
+	if err != nil {
+		return fmt.Errorf("can't open outbox: %w", err)
+	}
+	defer outbox.Close()
+
+	history, err := newHistory(env)
+	if err != nil {
+		return fmt.Errorf("can't open submission history: %w", err)
+	}
+	defer history.Close()
+
+	issueOwners, err := newIssueOwners(env)
+	if err != nil {
+		return fmt.Errorf("can't open issue owners storage: %w", err)
 	}
+	defer issueOwners.Close()
 
-	rapidApiClient := rapidapi.NewClient(env.RapidApiToken)
-	githubClient := github.NewClient(env.GitHubToken, env.GitHubRepo)
-	bot, err := telegram.NewBot(env.TelegramBotApiToken, rapidApiClient, githubClient, env.PublicUrl)
+	preferences, err := newPreferences(env)
 	if err != nil {
-		log.Fatalf("Can't start bot: %s", err.Error())
+		return fmt.Errorf("can't open preferences storage: %w", err)
+	}
+	defer preferences.Close()
+
+	canonicalizer, err := newCanonicalizer(env)
+	if err != nil {
+		return fmt.Errorf("can't load URL canonicalization rules: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: env.HttpTimeout, Transport: logging.NewTransport(http.DefaultTransport)}
+	labelSettings := github.LabelSettings{CreateMissing: env.GitHubCreateLabels, Color: env.GitHubLabelColor}
+	githubClient := github.NewClient(env.GitHubToken, env.GitHubRepo, labelSettings, config.IssueTemplates, httpClient)
+	stores := telegram.Stores{
+		State:       stateStorage,
+		Outbox:      outbox,
+		History:     history,
+		IssueOwners: issueOwners,
+		Preferences: preferences,
+	}
+	bot, err := telegram.NewBot(env.TelegramBotApiToken, newArticleExtractor(env, httpClient), githubClient, canonicalizer, stores, newBotSettings(env, config), newServerSettings(env), env.RequestTimeout)
+	if err != nil {
+		return fmt.Errorf("can't create bot: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := bot.Start(); err != nil {
+		return fmt.Errorf("can't start bot: %w", err)
+	}
+
+	var serverErr error
+	select {
+	case <-ctx.Done():
+	case serverErr = <-bot.Errors():
+		slog.Error("The HTTP server failed, shutting down", "error", serverErr)
+	}
+	// A second signal kills the bot right away.
+	stop()
+
+	slog.Info("Shutting down, waiting for the running handlers", "timeout", env.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), env.ShutdownTimeout)
+	defer cancel()
+	if err := bot.Stop(shutdownCtx); err != nil {
+		return fmt.Errorf("the bot didn't stop cleanly: %w", errors.Join(serverErr, err))
+	}
+	if serverErr != nil {
+		return serverErr
+	}
+	slog.Info("The bot is stopped")
+	return nil
+}
+
+func newLogger(env *Environment) *slog.Logger {
+	return logging.New(os.Stderr, logging.Settings{
+		Format:  env.LogFormat,
+		Level:   env.LogLevel,
+		Secrets: []string{env.TelegramBotApiToken, env.TelegramSecretToken, env.RapidApiToken, env.GitHubToken, env.GitHubWebhookSecret},
+	})
+}
+
+func newBotSettings(env *Environment, config *Config) telegram.Settings {
+	levels := config.Levels
+	if env.Levels != nil {
+		levels = env.Levels
+	}
+
+	return telegram.Settings{
+		Catalogs:       config.Catalogs,
+		Levels:         levels,
+		Topics:         config.Topics,
+		CreateLabels:   env.GitHubCreateLabels,
+		IssueTemplates: config.IssueTemplates,
+	}
+}
+
+func newServerSettings(env *Environment) telegram.ServerSettings {
+	return telegram.ServerSettings{
+		Polling:              env.TelegramMode == TelegramModePolling,
+		RemoveWebhook:        env.RemoveWebhook,
+		PublicUrl:            env.PublicUrl,
+		ListenAddress:        env.ListenAddress,
+		MetricsListenAddress: env.MetricsListenAddress,
+		SecretToken:          env.TelegramSecretToken,
+		TLSCertPath:          env.TLSCertPath,
+		TLSKeyPath:           env.TLSKeyPath,
+		GitHubWebhookSecret:  env.GitHubWebhookSecret,
+	}
+}
+
+func newStateStorage(env *Environment) (*telegram.StateStorage, error) {
+	if env.StorageType == StorageTypeFile {
+		path := filepath.Join(env.StorageDir, "state.journal")
+		fileStorage, err := storage.NewFileStorage[telegram.UserArticleState](path)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("The state is persisted", "path", path)
+		return telegram.NewStateStorage(fileStorage, env.DraftTimeout), nil
+	}
+
+	return telegram.NewStateStorage(storage.NewInMemoryStorage[telegram.UserArticleState](), env.DraftTimeout), nil
+}
+
+func newOutbox(env *Environment) (*telegram.Outbox, error) {
+	if env.StorageType == StorageTypeFile {
+		path := filepath.Join(env.StorageDir, "outbox.journal")
+		fileStorage, err := storage.NewFileStorage[telegram.Submission](path)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("The outbox is persisted", "path", path)
+		return telegram.NewOutbox(fileStorage), nil
+	}
+
+	return telegram.NewOutbox(storage.NewInMemoryStorage[telegram.Submission]()), nil
+}
+
+func newHistory(env *Environment) (*telegram.History, error) {
+	if env.StorageType == StorageTypeFile {
+		path := filepath.Join(env.StorageDir, "history.journal")
+		fileStorage, err := storage.NewFileStorage[[]telegram.SubmissionRecord](path)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("The submission history is persisted", "path", path)
+		return telegram.NewHistory(fileStorage), nil
+	}
+
+	return telegram.NewHistory(storage.NewInMemoryStorage[[]telegram.SubmissionRecord]()), nil
+}
+
+func newIssueOwners(env *Environment) (storage.Storage[telegram.IssueOwner], error) {
+	if env.StorageType == StorageTypeFile {
+		path := filepath.Join(env.StorageDir, "issue_owners.journal")
+		fileStorage, err := storage.NewFileStorage[telegram.IssueOwner](path)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("The issue owners are persisted", "path", path)
+		return fileStorage, nil
+	}
+
+	return storage.NewInMemoryStorage[telegram.IssueOwner](), nil
+}
+
+func newPreferences(env *Environment) (storage.Storage[telegram.Preferences], error) {
+	if env.StorageType == StorageTypeFile {
+		path := filepath.Join(env.StorageDir, "preferences.journal")
+		fileStorage, err := storage.NewFileStorage[telegram.Preferences](path)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("The user preferences are persisted", "path", path)
+		return fileStorage, nil
+	}
+
+	return storage.NewInMemoryStorage[telegram.Preferences](), nil
+}
+
+func newCanonicalizer(env *Environment) (*canonical.Canonicalizer, error) {
+	rules := canonical.DefaultRules()
+	if env.UrlRulesPath != "" {
+		customRules, err := canonical.LoadRules(env.UrlRulesPath)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, customRules...)
+	}
+
+	return canonical.NewCanonicalizer(rules), nil
+}
+
+func newArticleExtractor(env *Environment, httpClient *http.Client) extractor.Extractor {
+	extractors := make([]extractor.NamedExtractor, 0, len(env.ArticleExtractors))
+	for _, name := range env.ArticleExtractors {
+		switch name {
+		case ArticleExtractorRapidApi:
+			extractors = append(extractors, extractor.NamedExtractor{Name: name, Extractor: rapidapi.NewClient(env.RapidApiToken, httpClient)})
+		case ArticleExtractorHtml:
+			publicClient := &http.Client{Timeout: httpClient.Timeout, Transport: logging.NewTransport(extractor.NewPublicTransport())}
+			extractors = append(extractors, extractor.NamedExtractor{Name: name, Extractor: extractor.NewHtmlExtractor(publicClient)})
+		case ArticleExtractorUrl:
+			extractors = append(extractors, extractor.NamedExtractor{Name: name, Extractor: extractor.NewUrlExtractor()})
+		}
 	}
 
-	bot.Start()
+	slog.Info("Articles are extracted", "extractors", strings.Join(env.ArticleExtractors, ","))
+	return extractor.NewChain(extractors...)
 }
@@ -0,0 +1,26 @@
+package rapidapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{}
+	_ = json.Unmarshal(body, apiErr)
+	apiErr.StatusCode = statusCode
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	text := fmt.Sprintf("non-successful HTTP status code in ExtractArticle call: %d", e.StatusCode)
+	if e.Message != "" {
+		text += ", " + e.Message
+	}
+	return text
+}
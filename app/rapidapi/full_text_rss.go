@@ -1,8 +1,11 @@
 package rapidapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/retry"
 	"io"
 	"net/http"
 	"strings"
@@ -12,9 +15,11 @@ import (
 type Client struct {
 	apiKey            string
 	fullTextRssApiUrl string
+	httpClient        *http.Client
+	retryPolicy       retry.Policy
 }
 
-type Article struct {
+type extractResponse struct {
 	Title        string    `json:"title"`
 	Date         time.Time `json:"date"`
 	Author       string    `json:"author"`
@@ -28,17 +33,66 @@ func wrapError(err error) error {
 	return fmt.Errorf("error occurred during ExtractArticle call: %w", err)
 }
 
-func NewClient(apiKey string) *Client {
+func NewClient(apiKey string, httpClient *http.Client) *Client {
 	return &Client{
 		apiKey:            apiKey,
 		fullTextRssApiUrl: "https://full-text-rss.p.rapidapi.com/extract.php",
+		httpClient:        httpClient,
+		retryPolicy:       retry.DefaultPolicy(),
 	}
 }
 
-func (c *Client) ExtractArticle(articleUrl string) (*Article, error) {
+func (c *Client) ExtractArticle(ctx context.Context, articleUrl string) (*extractor.Article, error) {
+	var body []byte
+	err := c.retryPolicy.Do(ctx, func() error {
+		var err error
+		body, err = c.post(ctx, articleUrl)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response extractResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &extractor.Article{
+		Title:        response.Title,
+		Date:         response.Date,
+		Author:       response.Author,
+		Language:     response.Language,
+		Url:          response.Url,
+		EffectiveUrl: response.EffectiveUrl,
+		Domain:       response.Domain,
+	}, nil
+}
+
+// Check checks that the RapidAPI gateway is reachable without spending the quota.
+func (c *Client) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", c.fullTextRssApiUrl, nil)
+	if err != nil {
+		return fmt.Errorf("error occurred during Check call: %w", err)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error occurred during Check call: %w", err)
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode >= 500 {
+		return fmt.Errorf("non-successful HTTP status code in Check call: %d", res.StatusCode)
+	}
+	return nil
+}
+
+func (c *Client) post(ctx context.Context, articleUrl string) ([]byte, error) {
 	payload := strings.NewReader(fmt.Sprintf("url=%s&xss=1&lang=2&links=preserve&content=0", articleUrl))
 
-	req, err := http.NewRequest("POST", c.fullTextRssApiUrl, payload)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.fullTextRssApiUrl, payload)
 	if err != nil {
 		return nil, wrapError(err)
 	}
@@ -47,26 +101,31 @@ func (c *Client) ExtractArticle(articleUrl string) (*Article, error) {
 	req.Header.Add("X-RapidAPI-Key", c.apiKey)
 	req.Header.Add("X-RapidAPI-Host", "full-text-rss.p.rapidapi.com")
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.httpClient.Do(req)
 	if err != nil {
+		if retry.IsTransientError(ctx, err) {
+			return nil, &retry.RetryableError{Err: wrapError(err)}
+		}
 		return nil, wrapError(err)
 	}
 
 	defer res.Body.Close()
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
+		if retry.IsTransientError(ctx, err) {
+			return nil, &retry.RetryableError{Err: wrapError(err)}
+		}
 		return nil, wrapError(err)
 	}
 
 	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("non-successful HTTP status code in ExtractArticle call: %d", res.StatusCode)
-	}
-
-	var article Article
-	err = json.Unmarshal(body, &article)
-	if err != nil {
-		return nil, wrapError(err)
+		err = newAPIError(res.StatusCode, body)
+		// RapidAPI answers 429 once the plan quota is used up, a retry doesn't help.
+		if retry.IsTransientStatus(res.StatusCode) && res.StatusCode != http.StatusTooManyRequests {
+			return nil, &retry.RetryableError{Err: err, After: retry.ParseRetryAfter(res.Header.Get("Retry-After"), time.Now())}
+		}
+		return nil, err
 	}
 
-	return &article, nil
+	return body, nil
 }
@@ -1,12 +1,15 @@
 package rapidapi
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/retry"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,14 +32,15 @@ func TestExtractArticle_Success(t *testing.T) {
 	client := Client{
 		apiKey:            "FAKE_API_KEY",
 		fullTextRssApiUrl: mockServer.URL,
+		httpClient:        mockServer.Client(),
 	}
 
 	// Act
-	article, err := client.ExtractArticle("https://example.com")
+	article, err := client.ExtractArticle(context.Background(), "https://example.com")
 
 	// Assert
 	assert.Nil(t, err, "unexpected error")
-	expectedArticle := &Article{
+	expectedArticle := &extractor.Article{
 		Title:        "Sample Title",
 		Date:         time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC),
 		Author:       "John Doe",
@@ -58,10 +62,11 @@ func TestExtractArticle_NonSuccessHTTPStatus(t *testing.T) {
 	client := Client{
 		apiKey:            "FAKE_API_KEY",
 		fullTextRssApiUrl: mockServer.URL,
+		httpClient:        mockServer.Client(),
 	}
 
 	// Act
-	_, err := client.ExtractArticle("https://example.com")
+	_, err := client.ExtractArticle(context.Background(), "https://example.com")
 
 	// Assert
 	assert.NotNil(t, err, "expected non-nil error")
@@ -80,12 +85,184 @@ func TestExtractArticle_Failure(t *testing.T) {
 	client := Client{
 		apiKey:            "FAKE_API_KEY",
 		fullTextRssApiUrl: mockServer.URL,
+		httpClient:        mockServer.Client(),
 	}
 
 	// Act
-	_, err := client.ExtractArticle("https://example.com")
+	_, err := client.ExtractArticle(context.Background(), "https://example.com")
 
 	// Assert
 	assert.NotNil(t, err, "expected non-nil error")
 	assert.EqualError(t, err, "error occurred during ExtractArticle call: invalid character 'm' looking for beginning of value", "unexpected error message")
 }
+
+func TestExtractArticle_Cancelled(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer mockServer.Close()
+	defer close(release)
+
+	client := Client{
+		apiKey:            "FAKE_API_KEY",
+		fullTextRssApiUrl: mockServer.URL,
+		httpClient:        mockServer.Client(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Act
+	_, err := client.ExtractArticle(ctx, "https://example.com")
+
+	// Assert
+	assert.NotNil(t, err, "expected non-nil error")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "expected the deadline to reach the HTTP call")
+}
+
+func TestExtractArticle_RetriesServerError(t *testing.T) {
+	// Arrange
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"title": "Sample Title", "url": "https://example.com"}`))
+	}))
+	defer mockServer.Close()
+
+	client := Client{
+		apiKey:            "FAKE_API_KEY",
+		fullTextRssApiUrl: mockServer.URL,
+		httpClient:        mockServer.Client(),
+		retryPolicy:       retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	}
+
+	// Act
+	article, err := client.ExtractArticle(context.Background(), "https://example.com")
+
+	// Assert
+	assert.Nil(t, err, "unexpected error")
+	assert.Equal(t, "Sample Title", article.Title)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestExtractArticle_GivesUpOnServerError(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mockServer.Close()
+
+	client := Client{
+		apiKey:            "FAKE_API_KEY",
+		fullTextRssApiUrl: mockServer.URL,
+		httpClient:        mockServer.Client(),
+		retryPolicy:       retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	}
+
+	// Act
+	_, err := client.ExtractArticle(context.Background(), "https://example.com")
+
+	// Assert
+	var temporaryErr *retry.TemporaryError
+	assert.ErrorAs(t, err, &temporaryErr)
+	assert.EqualError(t, err, "non-successful HTTP status code in ExtractArticle call: 500 (gave up after 2 attempts)", "unexpected error message")
+}
+
+func TestExtractArticle_ApiError(t *testing.T) {
+	// Arrange
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "You are not subscribed to this API."}`))
+	}))
+	defer mockServer.Close()
+
+	client := Client{
+		apiKey:            "FAKE_API_KEY",
+		fullTextRssApiUrl: mockServer.URL,
+		httpClient:        mockServer.Client(),
+	}
+
+	// Act
+	_, err := client.ExtractArticle(context.Background(), "https://example.com")
+
+	// Assert
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, &APIError{StatusCode: http.StatusForbidden, Message: "You are not subscribed to this API."}, apiErr)
+	assert.EqualError(t, err, "non-successful HTTP status code in ExtractArticle call: 403, You are not subscribed to this API.", "unexpected error message")
+}
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statusCode int
+		expected   string
+	}{
+		{"reachable", http.StatusOK, ""},
+		{"method not allowed", http.StatusMethodNotAllowed, ""},
+		{"unavailable", http.StatusServiceUnavailable, "non-successful HTTP status code in Check call: 503"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "HEAD", r.Method, "unexpected method")
+				assert.Empty(t, r.Header.Get("X-RapidAPI-Key"), "unexpected X-RapidAPI-Key header")
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer mockServer.Close()
+
+			client := Client{
+				apiKey:            "FAKE_API_KEY",
+				fullTextRssApiUrl: mockServer.URL,
+				httpClient:        mockServer.Client(),
+			}
+
+			// Act
+			err := client.Check(context.Background())
+
+			// Assert
+			if tc.expected == "" {
+				assert.Nil(t, err, "unexpected error")
+			} else {
+				assert.EqualError(t, err, tc.expected, "unexpected error message")
+			}
+		})
+	}
+}
+
+func TestExtractArticle_DoesNotRetryQuotaExceeded(t *testing.T) {
+	// Arrange
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message": "You have exceeded the MONTHLY quota for Requests on your current plan, BASIC."}`))
+	}))
+	defer mockServer.Close()
+
+	client := Client{
+		apiKey:            "FAKE_API_KEY",
+		fullTextRssApiUrl: mockServer.URL,
+		httpClient:        mockServer.Client(),
+		retryPolicy:       retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+	}
+
+	// Act
+	_, err := client.ExtractArticle(context.Background(), "https://example.com")
+
+	// Assert
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Policy struct {
+	MaxAttempts int
+	// BaseDelay is the delay before the second attempt, it doubles with every next attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps a single delay. A call asking to wait longer is not repeated.
+	MaxDelay time.Duration
+}
+
+type RetryableError struct {
+	Err   error
+	After time.Duration
+}
+
+type TemporaryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TemporaryError) Error() string {
+	return fmt.Sprintf("%s (gave up after %d attempts)", e.Err.Error(), e.Attempts)
+}
+
+func (e *TemporaryError) Unwrap() error {
+	return e.Err
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+func (p Policy) Do(ctx context.Context, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+
+		var retryable *RetryableError
+		if !errors.As(err, &retryable) {
+			return err
+		}
+
+		delay := p.backoff(attempt)
+		if retryable.After > delay {
+			delay = retryable.After
+		}
+		if attempt >= p.MaxAttempts || delay > p.MaxDelay {
+			return &TemporaryError{Attempts: attempt, Err: retryable.Err}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &TemporaryError{Attempts: attempt, Err: retryable.Err}
+		case <-timer.C:
+		}
+	}
+}
+
+func (p Policy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func IsTransientStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+func IsTransientError(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil
+}
+
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPolicy() Policy {
+	return Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	// Arrange
+	attempts := 0
+	call := func() error {
+		attempts++
+		if attempts < 3 {
+			return &RetryableError{Err: errors.New("unavailable")}
+		}
+		return nil
+	}
+
+	// Act
+	err := newTestPolicy().Do(context.Background(), call)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestDo_StopsOnPermanentError(t *testing.T) {
+	// Arrange
+	attempts := 0
+	call := func() error {
+		attempts++
+		return errors.New("not found")
+	}
+
+	// Act
+	err := newTestPolicy().Do(context.Background(), call)
+
+	// Assert
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestDo_GivesUpAfterMaxAttempts(t *testing.T) {
+	// Arrange
+	attempts := 0
+	cause := errors.New("unavailable")
+	call := func() error {
+		attempts++
+		return &RetryableError{Err: cause}
+	}
+
+	// Act
+	err := newTestPolicy().Do(context.Background(), call)
+
+	// Assert
+	var temporaryErr *TemporaryError
+	assert.ErrorAs(t, err, &temporaryErr)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, 3, temporaryErr.Attempts)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestDo_GivesUpWhenServerAsksToWaitTooLong(t *testing.T) {
+	// Arrange
+	attempts := 0
+	call := func() error {
+		attempts++
+		return &RetryableError{Err: errors.New("rate limited"), After: time.Hour}
+	}
+
+	// Act
+	err := newTestPolicy().Do(context.Background(), call)
+
+	// Assert
+	var temporaryErr *TemporaryError
+	assert.ErrorAs(t, err, &temporaryErr)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestDo_StopsWhenContextDone(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	call := func() error {
+		attempts++
+		cancel()
+		return &RetryableError{Err: errors.New("unavailable")}
+	}
+
+	// Act
+	err := Policy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second}.Do(ctx, call)
+
+	// Assert
+	var temporaryErr *TemporaryError
+	assert.ErrorAs(t, err, &temporaryErr)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestIsTransientStatus(t *testing.T) {
+	assert.True(t, IsTransientStatus(http.StatusTooManyRequests))
+	assert.True(t, IsTransientStatus(http.StatusBadGateway))
+	assert.False(t, IsTransientStatus(http.StatusNotFound))
+	assert.False(t, IsTransientStatus(http.StatusForbidden))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{"missing", "", 0},
+		{"seconds", "120", 2 * time.Minute},
+		{"http date", "Mon, 15 Jan 2024 12:00:30 GMT", 30 * time.Second},
+		{"past http date", "Mon, 15 Jan 2024 11:00:00 GMT", 0},
+		{"invalid", "soon", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual := ParseRetryAfter(tc.value, now)
+
+			// Assert
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
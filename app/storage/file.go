@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	opSet    = "set"
+	opDelete = "delete"
+
+	compactionThreshold = 1000
+)
+
+type journalRecord[T any] struct {
+	Op        string     `json:"op"`
+	Key       int64      `json:"key"`
+	Value     T          `json:"value,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type FileStorage[T any] struct {
+	mu      sync.RWMutex
+	m       map[int64]entry[T]
+	path    string
+	file    *os.File
+	records int
+}
+
+func NewFileStorage[T any](path string) (*FileStorage[T], error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("can't create storage directory: %w", err)
+	}
+
+	s := &FileStorage[T]{
+		m:    make(map[int64]entry[T]),
+		path: path,
+	}
+
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FileStorage[T]) Set(key int64, value T) {
+	s.SetWithTTL(key, value, 0)
+}
+
+func (s *FileStorage[T]) SetWithTTL(key int64, value T, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := newEntry(value, ttl)
+	s.m[key] = e
+	s.append(journalRecord[T]{Op: opSet, Key: key, Value: value, ExpiresAt: e.ExpiresAt})
+}
+
+func (s *FileStorage[T]) Get(key int64) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.m[key]
+	if !ok || e.expired(time.Now()) {
+		var zero T
+		return zero, false
+	}
+	return e.Value, true
+}
+
+func (s *FileStorage[T]) Delete(key int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.m[key]; !ok {
+		return
+	}
+	delete(s.m, key)
+	s.append(journalRecord[T]{Op: opDelete, Key: key})
+}
+
+func (s *FileStorage[T]) Items() map[int64]T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now()
+	items := make(map[int64]T, len(s.m))
+	for key, e := range s.m {
+		if !e.expired(now) {
+			items[key] = e.Value
+		}
+	}
+	return items
+}
+
+func (s *FileStorage[T]) Sweep(now time.Time) map[int64]T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expired := make(map[int64]T)
+	for key, e := range s.m {
+		if e.expired(now) {
+			expired[key] = e.Value
+			delete(s.m, key)
+			s.append(journalRecord[T]{Op: opDelete, Key: key})
+		}
+	}
+	return expired
+}
+
+func (s *FileStorage[T]) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *FileStorage[T]) append(record journalRecord[T]) {
+	if s.file == nil {
+		slog.Error("Storage journal is closed, the change is not persisted", "path", s.path)
+		return
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		slog.Error("Failed to encode storage journal record", "path", s.path, "error", err)
+		return
+	}
+
+	if _, err = s.file.Write(append(line, '\n')); err != nil {
+		slog.Error("Failed to write storage journal", "path", s.path, "error", err)
+		return
+	}
+
+	if err = s.file.Sync(); err != nil {
+		slog.Error("Failed to sync storage journal", "path", s.path, "error", err)
+		return
+	}
+
+	s.records++
+	if s.records > compactionThreshold && s.records > 2*len(s.m) {
+		if err = s.compact(); err != nil {
+			slog.Error("Failed to compact storage journal", "path", s.path, "error", err)
+		}
+	}
+}
+
+func (s *FileStorage[T]) replay() error {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can't open storage journal: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	lineNumber := 0
+	var pendingErr error
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNumber++
+			if pendingErr != nil {
+				return pendingErr
+			}
+
+			var record journalRecord[T]
+			if decodeErr := json.Unmarshal(line, &record); decodeErr != nil {
+				pendingErr = fmt.Errorf("corrupted storage journal %s at line %d: %w", s.path, lineNumber, decodeErr)
+				continue
+			}
+
+			switch record.Op {
+			case opSet:
+				s.m[record.Key] = entry[T]{Value: record.Value, ExpiresAt: record.ExpiresAt}
+			case opDelete:
+				delete(s.m, record.Key)
+			default:
+				pendingErr = fmt.Errorf("corrupted storage journal %s at line %d: unknown operation %q", s.path, lineNumber, record.Op)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("can't read storage journal: %w", err)
+		}
+	}
+
+	if pendingErr != nil {
+		slog.Warn("Skipping incomplete last record of storage journal", "path", s.path, "error", pendingErr)
+	}
+
+	return nil
+}
+
+func (s *FileStorage[T]) compact() error {
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("can't create storage snapshot: %w", err)
+	}
+
+	if err = s.writeSnapshot(tmp); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	// The snapshot stays open as the new journal, so the old one keeps working until the rename succeeds.
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("can't replace storage journal: %w", err)
+	}
+
+	if s.file != nil {
+		_ = s.file.Close()
+	}
+	s.file = tmp
+	s.records = len(s.m)
+
+	if err = syncDir(s.path); err != nil {
+		return fmt.Errorf("can't sync storage directory: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStorage[T]) writeSnapshot(file *os.File) error {
+	writer := bufio.NewWriter(file)
+	for key, e := range s.m {
+		line, err := json.Marshal(journalRecord[T]{Op: opSet, Key: key, Value: e.Value, ExpiresAt: e.ExpiresAt})
+		if err != nil {
+			return fmt.Errorf("can't encode storage snapshot: %w", err)
+		}
+		_, _ = writer.Write(append(line, '\n'))
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("can't write storage snapshot: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("can't sync storage snapshot: %w", err)
+	}
+	return nil
+}
+
+func syncDir(path string) error {
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testState struct {
+	Url    string
+	Topics []string
+}
+
+func TestFileStorage_SetAndGet(t *testing.T) {
+	// Arrange
+	storage, err := NewFileStorage[testState](filepath.Join(t.TempDir(), "state.journal"))
+	assert.NoError(t, err)
+	defer storage.Close()
+	key := int64(1)
+	value := testState{Url: "https://example.com", Topics: []string{"kafka"}}
+
+	// Act
+	storage.Set(key, value)
+	result, ok := storage.Get(key)
+
+	// Assert
+	assert.True(t, ok, "Expected key to be found")
+	assert.Equal(t, value, result, "Expected value to match")
+}
+
+func TestFileStorage_SurvivesReopen(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state.journal")
+	storage, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	storage.Set(1, testState{Url: "https://example.com/1"})
+	storage.Set(2, testState{Url: "https://example.com/2"})
+	storage.Set(1, testState{Url: "https://example.com/1", Topics: []string{"streaming"}})
+	storage.Delete(2)
+	assert.NoError(t, storage.Close())
+
+	// Act
+	reopened, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	defer reopened.Close()
+	first, firstOk := reopened.Get(1)
+	_, secondOk := reopened.Get(2)
+
+	// Assert
+	assert.True(t, firstOk, "Expected key to be restored from the journal")
+	assert.Equal(t, testState{Url: "https://example.com/1", Topics: []string{"streaming"}}, first)
+	assert.False(t, secondOk, "Expected deleted key to stay deleted after reopen")
+}
+
+func TestFileStorage_SkipsTruncatedLastRecord(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state.journal")
+	journal := "{\"op\":\"set\",\"key\":1,\"value\":{\"Url\":\"https://example.com\"}}\n{\"op\":\"set\",\"key\":2,\"val"
+	assert.NoError(t, os.WriteFile(path, []byte(journal), 0o600))
+
+	// Act
+	storage, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	defer storage.Close()
+	first, firstOk := storage.Get(1)
+	_, secondOk := storage.Get(2)
+
+	// Assert
+	assert.True(t, firstOk, "Expected complete record to be restored")
+	assert.Equal(t, "https://example.com", first.Url)
+	assert.False(t, secondOk, "Expected truncated record to be skipped")
+}
+
+func TestFileStorage_FailsOnCorruptedJournal(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state.journal")
+	journal := "garbage\n{\"op\":\"set\",\"key\":1,\"value\":{\"Url\":\"https://example.com\"}}\n"
+	assert.NoError(t, os.WriteFile(path, []byte(journal), 0o600))
+
+	// Act
+	_, err := NewFileStorage[testState](path)
+
+	// Assert
+	assert.Error(t, err, "Expected corrupted journal to be reported")
+}
+
+func TestFileStorage_SweepSurvivesReopen(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state.journal")
+	storage, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	storage.SetWithTTL(1, testState{Url: "https://example.com/1"}, time.Minute)
+	storage.SetWithTTL(2, testState{Url: "https://example.com/2"}, time.Hour)
+
+	// Act
+	expired := storage.Sweep(time.Now().Add(30 * time.Minute))
+	assert.NoError(t, storage.Close())
+	reopened, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	defer reopened.Close()
+	_, firstOk := reopened.Get(1)
+	_, secondOk := reopened.Get(2)
+
+	// Assert
+	assert.Equal(t, map[int64]testState{1: {Url: "https://example.com/1"}}, expired)
+	assert.False(t, firstOk, "Expected swept key to stay deleted after reopen")
+	assert.True(t, secondOk, "Expected key with TTL to be restored from the journal")
+}
+
+func TestFileStorage_ItemsSurviveReopen(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "outbox.journal")
+	storage, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	storage.Set(1, testState{Url: "https://example.com/1"})
+	storage.Set(2, testState{Url: "https://example.com/2"})
+	storage.Delete(1)
+	assert.NoError(t, storage.Close())
+
+	// Act
+	reopened, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	defer reopened.Close()
+	items := reopened.Items()
+
+	// Assert
+	assert.Equal(t, map[int64]testState{2: {Url: "https://example.com/2"}}, items)
+}
+
+func TestFileStorage_CompactsJournal(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state.journal")
+	storage, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+
+	// Act
+	for i := 0; i <= 2*compactionThreshold; i++ {
+		storage.Set(1, testState{Url: fmt.Sprintf("https://example.com/%d", i)})
+	}
+	storage.Set(2, testState{Url: "https://example.com/last"})
+	assert.NoError(t, storage.Close())
+
+	// Assert
+	reopened, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	defer reopened.Close()
+	assert.Equal(t, map[int64]testState{
+		1: {Url: fmt.Sprintf("https://example.com/%d", 2*compactionThreshold)},
+		2: {Url: "https://example.com/last"},
+	}, reopened.Items())
+	journal, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(journal, []byte("\n")), "Expected the journal to be compacted on reopen")
+}
+
+func TestFileStorage_KeepsJournalWhenCompactionFails(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "state.journal")
+	storage, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Mkdir(path+".tmp", 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(path+".tmp", "blocker"), nil, 0o600))
+
+	// Act
+	for i := 0; i <= compactionThreshold; i++ {
+		storage.Set(1, testState{Url: fmt.Sprintf("https://example.com/%d", i)})
+	}
+	storage.Set(2, testState{Url: "https://example.com/last"})
+	assert.NoError(t, storage.Close())
+
+	// Assert
+	assert.NoError(t, os.RemoveAll(path+".tmp"))
+	reopened, err := NewFileStorage[testState](path)
+	assert.NoError(t, err)
+	defer reopened.Close()
+	last, ok := reopened.Get(2)
+	assert.True(t, ok, "Expected the writes after a failed compaction to be persisted")
+	assert.Equal(t, testState{Url: "https://example.com/last"}, last)
+}
@@ -2,30 +2,39 @@ package storage
 
 import (
 	"sync"
+	"time"
 )
 
 type InMemoryStorage[T any] struct {
 	mu sync.RWMutex
-	m  map[int64]T
+	m  map[int64]entry[T]
 }
 
 func NewInMemoryStorage[T any]() *InMemoryStorage[T] {
 	return &InMemoryStorage[T]{
-		m: make(map[int64]T),
+		m: make(map[int64]entry[T]),
 	}
 }
 
 func (s *InMemoryStorage[T]) Set(key int64, value T) {
+	s.SetWithTTL(key, value, 0)
+}
+
+func (s *InMemoryStorage[T]) SetWithTTL(key int64, value T, ttl time.Duration) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.m[key] = value
+	s.m[key] = newEntry(value, ttl)
 }
 
 func (s *InMemoryStorage[T]) Get(key int64) (T, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	value, ok := s.m[key]
-	return value, ok
+	e, ok := s.m[key]
+	if !ok || e.expired(time.Now()) {
+		var zero T
+		return zero, false
+	}
+	return e.Value, true
 }
 
 func (s *InMemoryStorage[T]) Delete(key int64) {
@@ -33,3 +42,33 @@ func (s *InMemoryStorage[T]) Delete(key int64) {
 	defer s.mu.Unlock()
 	delete(s.m, key)
 }
+
+func (s *InMemoryStorage[T]) Items() map[int64]T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now()
+	items := make(map[int64]T, len(s.m))
+	for key, e := range s.m {
+		if !e.expired(now) {
+			items[key] = e.Value
+		}
+	}
+	return items
+}
+
+func (s *InMemoryStorage[T]) Sweep(now time.Time) map[int64]T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expired := make(map[int64]T)
+	for key, e := range s.m {
+		if e.expired(now) {
+			expired[key] = e.Value
+			delete(s.m, key)
+		}
+	}
+	return expired
+}
+
+func (s *InMemoryStorage[T]) Close() error {
+	return nil
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestInMemoryStorage_SetAndGet(t *testing.T) {
@@ -48,3 +49,55 @@ func TestInMemoryStorage_GetNotFound(t *testing.T) {
 	assert.False(t, ok, "Expected key to not be found")
 	assert.Equal(t, "", result, "Expected value to be empty")
 }
+
+func TestInMemoryStorage_GetExpired(t *testing.T) {
+	// Arrange
+	storage := NewInMemoryStorage[string]()
+	key := int64(1)
+
+	// Act
+	storage.SetWithTTL(key, "test", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	result, ok := storage.Get(key)
+
+	// Assert
+	assert.False(t, ok, "Expected expired key to not be found")
+	assert.Equal(t, "", result, "Expected value to be empty")
+}
+
+func TestInMemoryStorage_Sweep(t *testing.T) {
+	// Arrange
+	storage := NewInMemoryStorage[string]()
+	storage.SetWithTTL(1, "expiring", time.Minute)
+	storage.SetWithTTL(2, "fresh", time.Hour)
+	storage.Set(3, "permanent")
+
+	// Act
+	expired := storage.Sweep(time.Now().Add(30 * time.Minute).Add(time.Second))
+	_, expiringOk := storage.Get(1)
+	fresh, freshOk := storage.Get(2)
+	permanent, permanentOk := storage.Get(3)
+
+	// Assert
+	assert.Equal(t, map[int64]string{1: "expiring"}, expired, "Expected only the expired entry to be swept")
+	assert.False(t, expiringOk, "Expected swept key to be removed")
+	assert.True(t, freshOk, "Expected fresh key to be kept")
+	assert.Equal(t, "fresh", fresh)
+	assert.True(t, permanentOk, "Expected key without TTL to be kept")
+	assert.Equal(t, "permanent", permanent)
+}
+
+func TestInMemoryStorage_Items(t *testing.T) {
+	// Arrange
+	storage := NewInMemoryStorage[string]()
+	storage.Set(1, "first")
+	storage.SetWithTTL(2, "second", time.Hour)
+	storage.SetWithTTL(3, "expired", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	// Act
+	items := storage.Items()
+
+	// Assert
+	assert.Equal(t, map[int64]string{1: "first", 2: "second"}, items, "Expected only live entries")
+}
@@ -0,0 +1,31 @@
+package storage
+
+import "time"
+
+type Storage[T any] interface {
+	Set(key int64, value T)
+	SetWithTTL(key int64, value T, ttl time.Duration)
+	Get(key int64) (T, bool)
+	Delete(key int64)
+	Items() map[int64]T
+	Sweep(now time.Time) map[int64]T
+	Close() error
+}
+
+type entry[T any] struct {
+	Value     T          `json:"value"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func newEntry[T any](value T, ttl time.Duration) entry[T] {
+	e := entry[T]{Value: value}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		e.ExpiresAt = &expiresAt
+	}
+	return e
+}
+
+func (e entry[T]) expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+func RunSweeper[T any](ctx context.Context, s Storage[T], interval time.Duration, onExpired func(key int64, value T)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for key, value := range s.Sweep(now) {
+				onExpired(key, value)
+			}
+		}
+	}
+}
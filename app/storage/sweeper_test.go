@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSweeper_CallsOnExpired(t *testing.T) {
+	// Arrange
+	storage := NewInMemoryStorage[string]()
+	storage.SetWithTTL(1, "expiring", time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	expired := make(chan int64, 1)
+
+	// Act
+	go RunSweeper[string](ctx, storage, 5*time.Millisecond, func(key int64, value string) {
+		expired <- key
+	})
+
+	// Assert
+	select {
+	case key := <-expired:
+		assert.Equal(t, int64(1), key, "Expected expired key to be reported")
+	case <-time.After(time.Second):
+		t.Fatal("Expected expired entry to be swept")
+	}
+}
@@ -1,155 +1,738 @@
 package telegram
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/deordie/deordie-bot/app/canonical"
+	"github.com/deordie/deordie-bot/app/extractor"
 	"github.com/deordie/deordie-bot/app/github"
-	"github.com/deordie/deordie-bot/app/rapidapi"
+	"github.com/deordie/deordie-bot/app/health"
+	"github.com/deordie/deordie-bot/app/logging"
+	"github.com/deordie/deordie-bot/app/storage"
 	tele "gopkg.in/telebot.v3"
-	"log"
+	"log/slog"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
 	startCommand      = "/start"
 	newArticleCommand = "/newarticle"
 	helpCommand       = "/help"
+	backCommand       = "/back"
+	editCommand       = "/edit"
+
+	continueButton  = "continue"
+	submitButton    = "submit"
+	commentButton   = "comment"
+	editButton      = "edit"
+	editFieldButton = "edit_field"
+	cancelButton    = "cancel"
+
+	draftSweepInterval = time.Minute
+	pollingTimeout     = 10 * time.Second
 )
 
+type messageSender interface {
+	Send(to tele.Recipient, what interface{}, opts ...interface{}) (*tele.Message, error)
+}
+
 type articleExtractor interface {
-	ExtractArticle(articleUrl string) (*rapidapi.Article, error)
+	ExtractArticle(ctx context.Context, articleUrl string) (*extractor.Article, error)
+}
+
+type urlCanonicalizer interface {
+	Canonicalize(rawUrl string) (string, error)
 }
 
 type githubIssueCreator interface {
-	CreateIssue(article *github.ArticleIssue) (string, error)
+	CreateIssue(ctx context.Context, article *github.ArticleIssue) (string, error)
+}
+
+type githubIssueFinder interface {
+	FindIssueByUrl(ctx context.Context, articleUrl string) (*github.Issue, error)
+}
+
+type githubLabelLister interface {
+	ListLabels(ctx context.Context) ([]github.Label, error)
+}
+
+type githubIssueGetter interface {
+	GetIssue(ctx context.Context, issueNumber int) (*github.Issue, error)
+}
+
+type githubIssueCommenter interface {
+	AddComment(ctx context.Context, issueNumber int, article *github.ArticleIssue) (string, error)
+}
+
+type Settings struct {
+	Catalogs       map[string]*Messages
+	Levels         []string
+	Topics         []string
+	CreateLabels   bool
+	IssueTemplates *github.IssueTemplates
+}
+
+type Stores struct {
+	State       *StateStorage
+	Outbox      *Outbox
+	History     *History
+	IssueOwners storage.Storage[IssueOwner]
+	Preferences storage.Storage[Preferences]
 }
 
 type Bot struct {
 	telebot            *tele.Bot
+	poller             *tele.LongPoller
+	stopPolling        chan struct{}
+	pollingDone        chan struct{}
+	githubWebhook      http.Handler
+	server             ServerSettings
+	httpServer         *http.Server
+	metricsServer      *http.Server
+	serverErrors       chan error
+	readiness          *health.Readiness
+	metrics            *botMetrics
+	inFlight           inFlight
+	stopWorkers        context.CancelFunc
+	workers            sync.WaitGroup
+	sender             messageSender
 	articleExtractor   articleExtractor
+	urlCanonicalizer   urlCanonicalizer
 	githubIssueCreator githubIssueCreator
+	githubIssueFinder  githubIssueFinder
+	githubCommenter    githubIssueCommenter
+	githubIssueGetter  githubIssueGetter
+	labelCache         *labelCache
 	stateStorage       *StateStorage
+	outbox             *Outbox
+	history            *History
+	issueOwners        storage.Storage[IssueOwner]
+	preferences        storage.Storage[Preferences]
+	catalogs           map[string]*Messages
+	levels             []string
+	topics             []string
+	createLabels       bool
+	issueTemplates     *github.IssueTemplates
+	requestTimeout     time.Duration
 }
 
-func NewBot(token string, rapidApiClient *rapidapi.Client, githubClient *github.Client, publicUrl string) (*Bot, error) {
+func NewBot(token string, articleExtractor articleExtractor, githubClient *github.Client, canonicalizer *canonical.Canonicalizer, stores Stores, settings Settings, server ServerSettings, requestTimeout time.Duration) (*Bot, error) {
+	var poller *tele.LongPoller
+	if server.Polling {
+		poller = &tele.LongPoller{Timeout: pollingTimeout}
+		slog.Info("The bot is configured to fetch updates with long polling")
+	} else {
+		slog.Info("The bot is configured as a Webhook", "public_url", server.PublicUrl, "listen_address", server.ListenAddress)
+		if server.SecretToken == "" {
+			slog.Warn("TELEGRAM_SECRET_TOKEN is not set, so the Webhook accepts updates from anyone who knows its URL")
+		}
+	}
+
 	pref := tele.Settings{
-		Token:  token,
-		Poller: &tele.Webhook{Listen: ":8080", Endpoint: &tele.WebhookEndpoint{PublicURL: publicUrl}},
+		Token:       token,
+		Synchronous: true,
+		OnError:     handleError,
 	}
 	telebot, err := tele.NewBot(pref)
 	if err != nil {
 		return nil, fmt.Errorf("error occured during Telgram bot creation: %w", err)
 	}
 
-	log.Printf("The bot is configured as a Webhook with public URL: %s and listens on port 8080\n", publicUrl)
-
-	return &Bot{
+	botMetrics := newBotMetrics(stores.State)
+	instrumentedGithub := &instrumentedGitHub{next: githubClient, metrics: botMetrics}
+	b := &Bot{
 		telebot:            telebot,
-		articleExtractor:   rapidApiClient,
-		githubIssueCreator: githubClient,
-		stateStorage:       NewStateStorage(),
-	}, nil
+		poller:             poller,
+		server:             server,
+		readiness:          newReadiness(telebot, token, githubClient, articleExtractor),
+		metrics:            botMetrics,
+		sender:             telebot,
+		articleExtractor:   &instrumentedExtractor{next: articleExtractor, metrics: botMetrics},
+		urlCanonicalizer:   canonicalizer,
+		githubIssueCreator: instrumentedGithub,
+		githubIssueFinder:  instrumentedGithub,
+		githubCommenter:    instrumentedGithub,
+		githubIssueGetter:  instrumentedGithub,
+		labelCache:         newLabelCache(instrumentedGithub, labelCacheTtl),
+		stateStorage:       stores.State,
+		outbox:             stores.Outbox,
+		history:            stores.History,
+		issueOwners:        stores.IssueOwners,
+		preferences:        stores.Preferences,
+		catalogs:           settings.Catalogs,
+		levels:             settings.Levels,
+		topics:             settings.Topics,
+		createLabels:       settings.CreateLabels,
+		issueTemplates:     settings.IssueTemplates,
+		requestTimeout:     requestTimeout,
+	}
+
+	if server.GitHubWebhookSecret != "" {
+		b.githubWebhook = github.NewWebhookHandler(server.GitHubWebhookSecret, githubClient.Repo(), b.handleIssueEvent)
+		slog.Info("GitHub issues events are received by the webhook", "path", githubWebhookPath)
+	}
+	return b, nil
 }
 
-func (b *Bot) Start() {
+func (b *Bot) Start() error {
+	if b.poller != nil {
+		if err := b.removeWebhook(); err != nil {
+			return err
+		}
+	}
+
+	b.telebot.Use(correlate)
 	b.telebot.Handle(startCommand, b.handleStart)
 	b.telebot.Handle(newArticleCommand, b.handleNewArticle)
 	b.telebot.Handle(helpCommand, b.handleHelp)
+	b.telebot.Handle(backCommand, b.handleBack)
+	b.telebot.Handle(editCommand, b.handleEditCommand)
+	b.telebot.Handle(mySubmissionsCommand, b.handleMySubmissions)
+	b.telebot.Handle(languageCommand, b.handleLanguage)
 	b.telebot.Handle(tele.OnText, b.handleOnText)
+	b.telebot.Handle(&tele.Btn{Unique: continueButton}, b.handleContinue)
+	b.telebot.Handle(&tele.Btn{Unique: submitButton}, b.handleSubmit)
+	b.telebot.Handle(&tele.Btn{Unique: commentButton}, b.handleAddComment)
+	b.telebot.Handle(&tele.Btn{Unique: editButton}, b.handleEdit)
+	b.telebot.Handle(&tele.Btn{Unique: editFieldButton}, b.handleEditField)
+	b.telebot.Handle(&tele.Btn{Unique: cancelButton}, b.handleCancel)
+	b.telebot.Handle(&tele.Btn{Unique: languageButton}, b.handleLanguageButton)
+	b.telebot.Handle(&tele.Btn{Unique: levelButton}, b.handleLevel)
+	b.telebot.Handle(&tele.Btn{Unique: topicButton}, b.handleTopicToggle)
+	b.telebot.Handle(&tele.Btn{Unique: topicsPageButton}, b.handleTopicsPage)
+	b.telebot.Handle(&tele.Btn{Unique: topicsDoneButton}, b.handleTopicsDone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.stopWorkers = cancel
+	b.workers.Add(2)
+	go func() {
+		defer b.workers.Done()
+		storage.RunSweeper[UserArticleState](ctx, b.stateStorage, draftSweepInterval, b.handleDraftExpired)
+	}()
+	go func() {
+		defer b.workers.Done()
+		b.runOutboxWorker(ctx)
+	}()
+
+	b.serverErrors = make(chan error, 2)
+	b.httpServer = b.newServer()
+	go b.serve(b.httpServer, b.server.TLSCertPath != "")
+	if b.server.MetricsListenAddress != "" {
+		b.metricsServer = b.newMetricsServer()
+		go b.serve(b.metricsServer, false)
+	}
+
+	if b.poller != nil {
+		b.stopPolling = make(chan struct{})
+		b.pollingDone = make(chan struct{})
+		go b.poll()
+	} else {
+		webhook := &tele.Webhook{SecretToken: b.server.SecretToken, Endpoint: &tele.WebhookEndpoint{PublicURL: b.server.PublicUrl}}
+		if err := b.telebot.SetWebhook(webhook); err != nil {
+			return fmt.Errorf("error occurred during SetWebhook call: %w", err)
+		}
+	}
+
+	slog.Info("The bot is running", "listen_address", b.server.ListenAddress, "metrics_listen_address", b.server.MetricsListenAddress)
+	return nil
+}
+
+func (b *Bot) removeWebhook() error {
+	webhook, err := b.telebot.Webhook()
+	if err != nil {
+		return fmt.Errorf("error occurred during getWebhookInfo call: %w", err)
+	}
+	if webhook.Listen == "" {
+		return nil
+	}
+	if !b.server.RemoveWebhook {
+		return fmt.Errorf("the Webhook %s is set for the bot, set TELEGRAM_REMOVE_WEBHOOK=true to remove it and poll the updates", webhook.Listen)
+	}
+
+	slog.Warn("Removing the Webhook to poll the updates, it doesn't receive them anymore", "url", webhook.Listen)
+	if err := b.telebot.RemoveWebhook(); err != nil {
+		return fmt.Errorf("error occurred during RemoveWebhook call: %w", err)
+	}
+	return nil
+}
+
+func (b *Bot) poll() {
+	updates := make(chan tele.Update)
+	go func() {
+		b.poller.Poll(b.telebot, updates, b.stopPolling)
+		close(updates)
+	}()
+
+	for update := range updates {
+		b.dispatch(update)
+	}
+	close(b.pollingDone)
+}
 
-	log.Printf("The bot is running...\n")
-	b.telebot.Start()
+func (b *Bot) dispatch(update tele.Update) {
+	b.inFlight.run(func() {
+		b.telebot.ProcessUpdate(update)
+	})
 }
 
 func (b *Bot) handleStart(ctx tele.Context) error {
-	var startText = fmt.Sprintf("Hello! I can help you to quickly propose an article for DE or DIE: Digest. Start with %s command and follow the instructions.", newArticleCommand)
-	return ctx.Send(startText, tele.RemoveKeyboard)
+	m := b.messages(ctx)
+	return ctx.Send(fmt.Sprintf(m.Start, newArticleCommand), tele.RemoveKeyboard)
 }
 
 func (b *Bot) handleHelp(ctx tele.Context) error {
-	helpText := fmt.Sprintf(`Supported commands:
-%s - Propose an article for DE or DIE: Digest.`, newArticleCommand)
+	m := b.messages(ctx)
+	helpText := fmt.Sprintf(m.Help, newArticleCommand, backCommand, editCommand, mySubmissionsCommand, languageCommand)
 	return ctx.Send(helpText, tele.RemoveKeyboard)
 }
 
+func (b *Bot) handleDraftExpired(userId int64, _ UserArticleState) {
+	m := b.catalogs[b.language(userId, "")]
+	text := fmt.Sprintf(m.DraftExpired, newArticleCommand)
+	if _, err := b.sender.Send(tele.ChatID(userId), text, tele.RemoveKeyboard); err != nil {
+		slog.Error("Failed to notify user about expired draft", "user_id", userId, "error", err)
+	}
+}
+
 func (b *Bot) handleNewArticle(ctx tele.Context) error {
-	b.stateStorage.Set(ctx.Sender().ID, UserArticleState{UserId: ctx.Sender().ID})
-	return ctx.Send("Step 1. Provide article URL. To abort the operation type \"cancel\".", tele.RemoveKeyboard)
+	state := UserArticleState{UserId: ctx.Sender().ID, Step: stepUrl}
+	b.stateStorage.Set(state.UserId, state)
+	return b.promptStep(ctx, &state)
 }
 
 func (b *Bot) handleOnText(ctx tele.Context) error {
+	m := b.messages(ctx)
 	userId := ctx.Sender().ID
 	state, ok := b.stateStorage.Get(userId)
 	if !ok {
 		return nil
 	}
 
-	if strings.ToLower(ctx.Text()) == "cancel" {
+	if strings.EqualFold(ctx.Text(), "cancel") || strings.EqualFold(ctx.Text(), m.CancelKeyword) {
+		b.metrics.cancellations.WithLabelValues(state.currentStep()).Inc()
 		b.stateStorage.Delete(userId)
-		return ctx.Send("The operation was cancelled.", tele.RemoveKeyboard)
+		return ctx.Send(m.Cancelled, tele.RemoveKeyboard)
 	}
 
-	if state.Url == "" {
+	switch state.currentStep() {
+	case stepUrl, stepArticle:
 		validatedUrl, err := url.ParseRequestURI(ctx.Text())
 		if err != nil {
-			return ctx.Send("Provided input is not a valid URL, please fix the URL or abort the operation by typing \"cancel\".")
+			return ctx.Send(fmt.Sprintf(m.InvalidUrl, m.CancelKeyword))
 		}
 
-		state.Url = validatedUrl.String()
-		b.stateStorage.Set(userId, state)
-		return ctx.Send("Step 2. Provide article description as a plain text. To abort the operation type \"cancel\".")
-	}
+		callCtx, cancel := b.newCallContext(requestContext(ctx))
+		defer cancel()
 
-	if state.Description == "" {
+		article, err := b.extractArticle(callCtx, validatedUrl.String())
+		if err != nil {
+			slog.WarnContext(callCtx, "Failed to extract article", "url", validatedUrl.String(), "error", err)
+			return ctx.Send(fmt.Sprintf(m.ExtractFailed, m.CancelKeyword))
+		}
+		if article.TitleGuessed() {
+			slog.WarnContext(callCtx, "Article title is guessed from the URL", "url", validatedUrl.String(), "sources", article.Sources)
+		}
+
+		state.Url = b.canonicalizeUrl(validatedUrl.String())
+		state.Article = article
+		state.Step = stepArticle
+		b.stateStorage.Set(userId, state)
+		return b.promptStep(ctx, &state)
+	case stepDescription:
 		state.Description = ctx.Text()
+	case stepLevel:
+		return b.setLevel(ctx, &state, ctx.Text())
+	case stepTopics:
+		// Typed topics are added to the selection, the step is finished with the "Done" button.
+		topics, suggestions := b.matchTopics(requestContext(ctx), m, strings.Split(ctx.Text(), ","))
+		for _, topic := range topics {
+			state.Topics = addTopic(state.Topics, topic)
+		}
+		state.Step = stepTopics
 		b.stateStorage.Set(userId, state)
-		return ctx.Send("Step 3. Provide level. To abort the operation type \"cancel\".", getLevelKeyboard())
+		if suggestions != "" {
+			if err := ctx.Send(suggestions); err != nil {
+				return err
+			}
+		}
+		return b.sendTopicPicker(ctx, &state)
+	default:
+		return ctx.Send(m.UsePreviewButtons)
 	}
 
-	if state.Level == "" {
-		state.Level = ctx.Text()
-		b.stateStorage.Set(userId, state)
-		return ctx.Send("Step 4. Provide topics as a comma separated list, e.g. \"streaming, storage-engine, kafka\" without quotes. To abort the operation type \"cancel\".", tele.RemoveKeyboard)
+	state.Step = state.nextStep()
+	b.stateStorage.Set(userId, state)
+	return b.promptStep(ctx, &state)
+}
+
+func (b *Bot) handleBack(ctx tele.Context) error {
+	m := b.messages(ctx)
+	userId := ctx.Sender().ID
+	state, ok := b.stateStorage.Get(userId)
+	if !ok {
+		return ctx.Send(fmt.Sprintf(m.NoDraftToEdit, newArticleCommand))
+	}
+
+	state.Step = state.currentStep()
+	previousStep, ok := state.previousStep()
+	if !ok {
+		return ctx.Send(m.FirstStep)
+	}
+
+	state.Step = previousStep
+	b.stateStorage.Set(userId, state)
+	return b.promptStep(ctx, &state)
+}
+
+func (b *Bot) handleEditCommand(ctx tele.Context) error {
+	args := ctx.Args()
+	if len(args) == 0 {
+		return b.handleEdit(ctx)
+	}
+	return b.editField(ctx, strings.ToLower(args[0]))
+}
+
+func (b *Bot) promptStep(ctx tele.Context, state *UserArticleState) error {
+	m := b.messages(ctx)
+	if state.currentStep() == stepArticle && state.Article == nil {
+		// Drafts stored before the article step have no metadata, the URL is asked again to fetch it.
+		state.Step = stepUrl
+		b.stateStorage.Set(state.UserId, *state)
+	}
+	b.metrics.draftSteps.WithLabelValues(state.currentStep()).Inc()
+	var prompt, currentValue string
+	var keyboard interface{} = tele.RemoveKeyboard
+	switch state.currentStep() {
+	case stepUrl:
+		prompt = fmt.Sprintf(m.UrlPrompt, m.CancelKeyword)
+		currentValue = state.Url
+	case stepArticle:
+		return ctx.Send(formatDetectedArticle(m, state.Article), getArticleKeyboard(m))
+	case stepDescription:
+		prompt = fmt.Sprintf(m.DescriptionPrompt, m.CancelKeyword)
+		currentValue = state.Description
+	case stepLevel:
+		prompt = fmt.Sprintf(m.LevelPrompt, m.CancelKeyword)
+		currentValue = state.Level
+		keyboard = b.getLevelKeyboard()
+	case stepTopics:
+		return b.sendTopicPicker(ctx, state)
+	default:
+		return b.sendPreview(ctx, state)
+	}
+
+	if currentValue != "" {
+		prompt += fmt.Sprintf(m.CurrentValue, currentValue)
+	}
+	return ctx.Send(prompt, keyboard)
+}
+
+func (b *Bot) handleContinue(ctx tele.Context) error {
+	_ = ctx.Respond()
+
+	userId := ctx.Sender().ID
+	state, ok := b.stateStorage.Get(userId)
+	if !ok || state.currentStep() != stepArticle {
+		return nil
 	}
 
-	if len(state.Topics) == 0 {
-		topics := strings.Split(ctx.Text(), ",")
-		for i := range topics {
-			topics[i] = strings.TrimSpace(topics[i])
+	state.Step = state.nextStep()
+	b.stateStorage.Set(userId, state)
+	return b.promptStep(ctx, &state)
+}
+
+func (b *Bot) sendPreview(ctx tele.Context, state *UserArticleState) error {
+	m := b.messages(ctx)
+	if state.Article == nil {
+		callCtx, cancel := b.newCallContext(requestContext(ctx))
+		defer cancel()
+
+		article, err := b.extractArticle(callCtx, state.Url)
+		if err != nil {
+			slog.WarnContext(callCtx, "Failed to extract article", "url", state.Url, "error", err)
+			b.stateStorage.Delete(state.UserId)
+			return ctx.Send(m.FetchFailed, tele.RemoveKeyboard)
 		}
-		state.Topics = topics
+		state.Article = article
+		b.stateStorage.Set(state.UserId, *state)
+	}
+
+	issue, err := b.issueTemplates.RenderIssue(newArticleIssue(ctx.Sender().Username, state.Article, state))
+	if err != nil {
+		slog.ErrorContext(requestContext(ctx), "Failed to render GitHub issue", "url", state.Url, "error", err)
+		b.stateStorage.Delete(state.UserId)
+		return ctx.Send(fmt.Sprintf(m.CreateIssueFailed, ""), tele.RemoveKeyboard)
+	}
+
+	labels := b.previewLabels(requestContext(ctx), issue.Labels)
+	preview := fmt.Sprintf(m.Preview, issue.Title, strings.Join(labels, ", "), issue.Body)
+	return ctx.Send(preview, getPreviewKeyboard(m))
+}
+
+func (b *Bot) previewLabels(ctx context.Context, names []string) []string {
+	callCtx, cancel := b.newCallContext(ctx)
+	defer cancel()
+
+	existing, err := b.labelCache.get(callCtx)
+	if err != nil {
+		slog.WarnContext(callCtx, "Failed to list GitHub labels", "error", err)
+		return names
+	}
+	resolved, missing := github.ResolveLabels(existing, names)
+	if !b.createLabels || len(missing) == 0 {
+		return resolved
+	}
+
+	for _, name := range missing {
+		existing = append(existing, github.Label{Name: name})
+	}
+	resolved, _ = github.ResolveLabels(existing, names)
+	return resolved
+}
+
+func (b *Bot) handleSubmit(ctx tele.Context) error {
+	_ = ctx.Respond()
+	m := b.messages(ctx)
+
+	userId := ctx.Sender().ID
+	state, ok := b.stateStorage.Get(userId)
+	if !ok || !state.isComplete() || state.Article == nil {
+		return ctx.Send(fmt.Sprintf(m.NoDraftToSubmit, newArticleCommand))
+	}
+
+	articleIssue := newArticleIssue(ctx.Sender().Username, state.Article, &state)
+
+	callCtx, cancel := b.newCallContext(requestContext(ctx))
+	defer cancel()
+
+	existingIssue, err := b.githubIssueFinder.FindIssueByUrl(callCtx, articleIssue.Url)
+	if err != nil {
+		slog.WarnContext(callCtx, "Failed to search for duplicate GitHub issues", "url", articleIssue.Url, "error", err)
+	} else if existingIssue != nil {
+		text := fmt.Sprintf(m.Duplicate, existingIssue.HtmlUrl)
+		return ctx.Send(text, getDuplicateKeyboard(m, existingIssue.Number))
 	}
 
 	b.stateStorage.Delete(userId)
 
-	article, err := b.articleExtractor.ExtractArticle(state.Url)
+	submission := Submission{
+		UserId:        userId,
+		Issue:         articleIssue,
+		Language:      b.language(userId, ctx.Sender().LanguageCode),
+		CorrelationId: logging.CorrelationId(callCtx),
+		// The outbox worker leaves the submission to the attempt below.
+		NextAttemptAt: time.Now().Add(b.requestTimeout),
+	}
+	issueUrl, queued, err := b.submit(b.outbox.Add(submission), submission, false)
+	if err != nil {
+		slog.ErrorContext(callCtx, "Failed to create GitHub issue", "url", articleIssue.Url, "queued", queued, "error", err)
+		if queued {
+			return ctx.Send(m.Queued)
+		}
+		return ctx.Send(fmt.Sprintf(m.CreateIssueFailed, getRejectionText(m, err)))
+	}
+
+	return ctx.Send(fmt.Sprintf(m.IssueCreated, issueUrl))
+}
+
+func (b *Bot) handleAddComment(ctx tele.Context) error {
+	_ = ctx.Respond()
+	m := b.messages(ctx)
+
+	userId := ctx.Sender().ID
+	state, ok := b.stateStorage.Get(userId)
+	if !ok || !state.isComplete() || state.Article == nil {
+		return ctx.Send(fmt.Sprintf(m.NoDraftToSubmit, newArticleCommand))
+	}
+
+	issueNumber, err := strconv.Atoi(ctx.Data())
 	if err != nil {
-		log.Printf("Failed to extract article: %s", err.Error())
-		return ctx.Send("Operation failed on fetching article.")
+		slog.WarnContext(requestContext(ctx), "Invalid issue number in callback data", "data", ctx.Data(), "error", err)
+		return ctx.Send(fmt.Sprintf(m.CommentFailed, ""))
 	}
 
-	articleIssue := newArticleIssue(ctx.Sender().Username, article, &state)
-	issueUrl, err := b.githubIssueCreator.CreateIssue(articleIssue)
+	b.stateStorage.Delete(userId)
+
+	articleIssue := newArticleIssue(ctx.Sender().Username, state.Article, &state)
+	callCtx, cancel := b.newCallContext(requestContext(ctx))
+	defer cancel()
+
+	commentUrl, err := b.githubCommenter.AddComment(callCtx, issueNumber, articleIssue)
 	if err != nil {
-		log.Printf("Failed to create GitHub issue: %s.\nExtracted article is:\n%v\n", err.Error(), article)
-		return ctx.Send("Operation failed on creating GitHub issue.")
+		slog.ErrorContext(callCtx, "Failed to add comment to GitHub issue", "issue_number", issueNumber, "error", err)
+		if retryLater, ok := getRetryLaterText(m, err); ok {
+			b.stateStorage.Set(userId, state)
+			return ctx.Send(fmt.Sprintf(m.CommentFailed, " "+retryLater), getDuplicateKeyboard(m, issueNumber))
+		}
+		return ctx.Send(fmt.Sprintf(m.CommentFailed, getRejectionText(m, err)))
+	}
+
+	return ctx.Send(fmt.Sprintf(m.CommentAdded, commentUrl))
+}
+
+func (b *Bot) handleEdit(ctx tele.Context) error {
+	_ = ctx.Respond()
+	m := b.messages(ctx)
+
+	if _, ok := b.stateStorage.Get(ctx.Sender().ID); !ok {
+		return ctx.Send(fmt.Sprintf(m.NoDraftToEdit, newArticleCommand))
 	}
 
-	return ctx.Send("The article was added to the digest candidates! GitHub issue link: " + issueUrl)
+	return ctx.Send(m.ChooseField, getEditFieldKeyboard(m))
 }
 
-func getLevelKeyboard() *tele.ReplyMarkup {
-	keyboard := &tele.ReplyMarkup{ResizeKeyboard: true, OneTimeKeyboard: true}
-	btnBeginner := keyboard.Text("beginner")
-	btnMedium := keyboard.Text("medium")
-	btnAdvanced := keyboard.Text("advanced")
-	keyboard.Reply(keyboard.Row(btnBeginner, btnMedium, btnAdvanced))
+func (b *Bot) handleEditField(ctx tele.Context) error {
+	_ = ctx.Respond()
+	return b.editField(ctx, ctx.Data())
+}
+
+func (b *Bot) editField(ctx tele.Context, field string) error {
+	m := b.messages(ctx)
+	userId := ctx.Sender().ID
+	state, ok := b.stateStorage.Get(userId)
+	if !ok {
+		return ctx.Send(fmt.Sprintf(m.NoDraftToEdit, newArticleCommand))
+	}
+
+	switch field {
+	case stepUrl, stepDescription, stepLevel, stepTopics:
+		state.Step = field
+	default:
+		return ctx.Send(m.UnknownField, getEditFieldKeyboard(m))
+	}
+
+	b.stateStorage.Set(userId, state)
+	return b.promptStep(ctx, &state)
+}
+
+func (b *Bot) handleCancel(ctx tele.Context) error {
+	_ = ctx.Respond()
+	m := b.messages(ctx)
+	userId := ctx.Sender().ID
+	if state, ok := b.stateStorage.Get(userId); ok {
+		b.metrics.cancellations.WithLabelValues(state.currentStep()).Inc()
+		b.stateStorage.Delete(userId)
+	}
+	return ctx.Send(m.Cancelled, tele.RemoveKeyboard)
+}
+
+func (b *Bot) newCallContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, b.requestTimeout)
+}
+
+func (b *Bot) extractArticle(ctx context.Context, articleUrl string) (*extractor.Article, error) {
+	article, err := b.articleExtractor.ExtractArticle(ctx, articleUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	article.Url = b.canonicalizeUrl(article.Url)
+	article.EffectiveUrl = b.canonicalizeUrl(article.EffectiveUrl)
+	return article, nil
+}
+
+func (b *Bot) canonicalizeUrl(rawUrl string) string {
+	if rawUrl == "" {
+		return rawUrl
+	}
+
+	canonicalUrl, err := b.urlCanonicalizer.Canonicalize(rawUrl)
+	if err != nil {
+		slog.Warn("Failed to canonicalize URL", "url", rawUrl, "error", err)
+		return rawUrl
+	}
+	return canonicalUrl
+}
+
+func getArticleKeyboard(m *Messages) *tele.ReplyMarkup {
+	keyboard := &tele.ReplyMarkup{}
+	btnContinue := keyboard.Data(m.ContinueButton, continueButton)
+	btnCancel := keyboard.Data(m.CancelButton, cancelButton)
+	keyboard.Inline(keyboard.Row(btnContinue, btnCancel))
 	return keyboard
 }
 
-func newArticleIssue(user string, article *rapidapi.Article, state *UserArticleState) *github.ArticleIssue {
+func getRetryLaterText(m *Messages, err error) (string, bool) {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		if rateLimitErr.ResetAt.IsZero() {
+			return m.RateLimit, true
+		}
+		return fmt.Sprintf(m.RateLimitUntil, rateLimitErr.ResetAt.UTC().Format("15:04")), true
+	}
+
+	if isTemporaryError(err) {
+		return m.GitHubUnavailable, true
+	}
+
+	return "", false
+}
+
+func getRejectionText(m *Messages, err error) string {
+	var apiErr *github.APIError
+	if errors.As(err, &apiErr) && apiErr.Reason() != "" {
+		return fmt.Sprintf(m.Rejected, strings.TrimSuffix(apiErr.Reason(), "."))
+	}
+	return ""
+}
+
+func getPreviewKeyboard(m *Messages) *tele.ReplyMarkup {
+	keyboard := &tele.ReplyMarkup{}
+	btnSubmit := keyboard.Data(m.SubmitButton, submitButton)
+	btnEdit := keyboard.Data(m.EditButton, editButton)
+	btnCancel := keyboard.Data(m.CancelButton, cancelButton)
+	keyboard.Inline(keyboard.Row(btnSubmit, btnEdit, btnCancel))
+	return keyboard
+}
+
+func getDuplicateKeyboard(m *Messages, issueNumber int) *tele.ReplyMarkup {
+	keyboard := &tele.ReplyMarkup{}
+	btnComment := keyboard.Data(m.CommentButton, commentButton, strconv.Itoa(issueNumber))
+	btnCancel := keyboard.Data(m.CancelButton, cancelButton)
+	keyboard.Inline(keyboard.Row(btnComment, btnCancel))
+	return keyboard
+}
+
+func getEditFieldKeyboard(m *Messages) *tele.ReplyMarkup {
+	keyboard := &tele.ReplyMarkup{}
+	btnUrl := keyboard.Data(m.UrlField, editFieldButton, stepUrl)
+	btnDescription := keyboard.Data(m.DescriptionField, editFieldButton, stepDescription)
+	btnLevel := keyboard.Data(m.LevelField, editFieldButton, stepLevel)
+	btnTopics := keyboard.Data(m.TopicsField, editFieldButton, stepTopics)
+	keyboard.Inline(keyboard.Row(btnUrl, btnDescription), keyboard.Row(btnLevel, btnTopics))
+	return keyboard
+}
+
+func formatDetectedArticle(m *Messages, article *extractor.Article) string {
+	valueOrUnknown := func(value string) string {
+		if value == "" {
+			return m.UnknownValue
+		}
+		return value
+	}
+
+	text := fmt.Sprintf(m.ArticleFound, valueOrUnknown(article.Title), valueOrUnknown(article.Author), valueOrUnknown(article.Language), m.CancelKeyword)
+	if article.TitleGuessed() {
+		return m.TitleGuessed + "\n\n" + text
+	}
+	return text
+}
+
+func newArticleIssue(user string, article *extractor.Article, state *UserArticleState) *github.ArticleIssue {
+	// The effective URL is the one after redirects, so it is the best candidate for deduplication.
+	articleUrl := article.EffectiveUrl
+	if articleUrl == "" {
+		articleUrl = article.Url
+	}
+
 	return &github.ArticleIssue{
-		Url:         article.Url,
+		Url:         articleUrl,
 		Title:       article.Title,
 		Author:      article.Author,
 		Description: state.Description,
@@ -1,13 +1,18 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
+	"github.com/deordie/deordie-bot/app/canonical"
+	"github.com/deordie/deordie-bot/app/extractor"
 	"github.com/deordie/deordie-bot/app/github"
-	"github.com/deordie/deordie-bot/app/rapidapi"
+	"github.com/deordie/deordie-bot/app/retry"
+	"github.com/deordie/deordie-bot/app/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	tele "gopkg.in/telebot.v3"
 	"testing"
+	"time"
 )
 
 type MockTelegramBotContext struct {
@@ -20,46 +25,119 @@ func (m *MockTelegramBotContext) Send(what interface{}, opts ...interface{}) err
 	return args.Error(0)
 }
 
+func (m *MockTelegramBotContext) Edit(what interface{}, opts ...interface{}) error {
+	args := m.Called(what, opts)
+	return args.Error(0)
+}
+
 func (m *MockTelegramBotContext) Text() string {
 	args := m.Called()
 	return args.String(0)
 }
 
+func (m *MockTelegramBotContext) Args() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockTelegramBotContext) Data() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+// Get returns nil like a context of an update not passed through the middleware.
+func (m *MockTelegramBotContext) Get(key string) interface{} {
+	return nil
+}
+
+func (m *MockTelegramBotContext) Respond(resp ...*tele.CallbackResponse) error {
+	return nil
+}
+
 func (m *MockTelegramBotContext) Sender() *tele.User {
 	args := m.Called()
 	return args.Get(0).(*tele.User)
 }
 
+type MockSender struct {
+	mock.Mock
+}
+
+func (m *MockSender) Send(to tele.Recipient, what interface{}, opts ...interface{}) (*tele.Message, error) {
+	args := m.Called(to, what, opts)
+	return nil, args.Error(0)
+}
+
 type MockRapidAPIClient struct {
 	mock.Mock
 }
 
-func (m *MockRapidAPIClient) ExtractArticle(articleUrl string) (*rapidapi.Article, error) {
+func (m *MockRapidAPIClient) ExtractArticle(_ context.Context, articleUrl string) (*extractor.Article, error) {
 	args := m.Called(articleUrl)
-	return args.Get(0).(*rapidapi.Article), args.Error(1)
+	return args.Get(0).(*extractor.Article), args.Error(1)
 }
 
 type MockGitHubClient struct {
 	mock.Mock
 }
 
-func (m *MockGitHubClient) CreateIssue(article *github.ArticleIssue) (string, error) {
+func (m *MockGitHubClient) CreateIssue(_ context.Context, article *github.ArticleIssue) (string, error) {
 	args := m.Called(article)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockGitHubClient) FindIssueByUrl(_ context.Context, articleUrl string) (*github.Issue, error) {
+	args := m.Called(articleUrl)
+	return args.Get(0).(*github.Issue), args.Error(1)
+}
+
+func (m *MockGitHubClient) ListLabels(_ context.Context) ([]github.Label, error) {
+	args := m.Called()
+	return args.Get(0).([]github.Label), args.Error(1)
+}
+
+func (m *MockGitHubClient) GetIssue(_ context.Context, issueNumber int) (*github.Issue, error) {
+	args := m.Called(issueNumber)
+	return args.Get(0).(*github.Issue), args.Error(1)
+}
+
+func (m *MockGitHubClient) AddComment(_ context.Context, issueNumber int, article *github.ArticleIssue) (string, error) {
+	args := m.Called(issueNumber, article)
+	return args.String(0), args.Error(1)
+}
+
 func newTestBot(rapidApiClient *MockRapidAPIClient, githubClient *MockGitHubClient) *Bot {
 	if rapidApiClient == nil {
 		rapidApiClient = new(MockRapidAPIClient)
 	}
 	if githubClient == nil {
 		githubClient = new(MockGitHubClient)
+		githubClient.On("ListLabels").Return([]github.Label(nil), nil).Maybe()
 	}
+	sender := new(MockSender)
+	sender.On("Send", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	stateStorage := NewStateStorage(storage.NewInMemoryStorage[UserArticleState](), time.Hour)
 	return &Bot{
 		telebot:            &tele.Bot{},
+		metrics:            newBotMetrics(stateStorage),
+		sender:             sender,
 		articleExtractor:   rapidApiClient,
+		urlCanonicalizer:   canonical.NewCanonicalizer(canonical.DefaultRules()),
 		githubIssueCreator: githubClient,
-		stateStorage:       NewStateStorage(),
+		githubIssueFinder:  githubClient,
+		githubCommenter:    githubClient,
+		githubIssueGetter:  githubClient,
+		labelCache:         newLabelCache(githubClient, time.Minute),
+		stateStorage:       stateStorage,
+		outbox:             NewOutbox(storage.NewInMemoryStorage[Submission]()),
+		history:            NewHistory(storage.NewInMemoryStorage[[]SubmissionRecord]()),
+		issueOwners:        storage.NewInMemoryStorage[IssueOwner](),
+		preferences:        storage.NewInMemoryStorage[Preferences](),
+		catalogs:           DefaultCatalogs(),
+		levels:             []string{"beginner", "medium", "advanced"},
+		createLabels:       true,
+		issueTemplates:     github.DefaultIssueTemplates(),
+		requestTimeout:     time.Second,
 	}
 }
 
@@ -67,6 +145,7 @@ func TestStartHandler(t *testing.T) {
 	bot := newTestBot(nil, nil)
 	mockContext := new(MockTelegramBotContext)
 	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: 1032})
 
 	_ = bot.handleStart(mockContext)
 
@@ -77,10 +156,16 @@ func TestHelpHandler(t *testing.T) {
 	bot := newTestBot(nil, nil)
 	mockContext := new(MockTelegramBotContext)
 	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: 1033})
 
 	_ = bot.handleHelp(mockContext)
 
-	mockContext.AssertCalled(t, "Send", "Supported commands:\n/newarticle - Propose an article for DE or DIE: Digest.", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "Supported commands:\n"+
+		"/newarticle - Propose an article for DE or DIE: Digest.\n"+
+		"/back - Return to the previous step of the article draft.\n"+
+		"/edit <field> - Change a single field of the article draft: url, description, level or topics.\n"+
+		"/mysubmissions - List your recent proposals and their state.\n"+
+		"/language - Choose the language of the bot.", mock.Anything)
 }
 
 func TestNewArticleHandler(t *testing.T) {
@@ -99,7 +184,10 @@ func TestNewArticleHandler(t *testing.T) {
 
 func TestOnTextHandler_WhenUrlState(t *testing.T) {
 	userId := int64(1001)
-	bot := newTestBot(nil, nil)
+	mockRapidApi := new(MockRapidAPIClient)
+	article := &extractor.Article{Title: "Article Title", Author: "Noname Blog", Language: "en", Url: "https://example.com"}
+	mockRapidApi.On("ExtractArticle", mock.Anything).Return(article, nil)
+	bot := newTestBot(mockRapidApi, nil)
 	mockContext := new(MockTelegramBotContext)
 	mockContext.On("Text").Return("https://example.com")
 	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
@@ -109,12 +197,96 @@ func TestOnTextHandler_WhenUrlState(t *testing.T) {
 	_ = bot.handleOnText(mockContext)
 
 	expectedState := UserArticleState{
-		UserId: userId,
-		Url:    "https://example.com",
+		UserId:  userId,
+		Step:    stepArticle,
+		Url:     "https://example.com",
+		Article: article,
 	}
 	actualState, ok := bot.stateStorage.Get(userId)
 	assert.True(t, ok)
 	assert.Equal(t, expectedState, actualState)
+	mockRapidApi.AssertCalled(t, "ExtractArticle", "https://example.com")
+	mockContext.AssertCalled(t, "Send", "Found the article:\nTitle: Article Title\nAuthor: Noname Blog\nLanguage: en\n\n"+
+		"Press \"Continue\" if this is the right article or send a corrected URL. To abort the operation type \"cancel\".", mock.Anything)
+}
+
+func TestOnTextHandler_WhenUrlStateAndTitleGuessed(t *testing.T) {
+	userId := int64(1034)
+	mockRapidApi := new(MockRapidAPIClient)
+	article := &extractor.Article{
+		Title:   "Dead Article",
+		Url:     "https://example.com/dead-article",
+		Sources: map[string]string{extractor.FieldTitle: extractor.SourceUrl, extractor.FieldUrl: extractor.SourceUrl},
+	}
+	mockRapidApi.On("ExtractArticle", mock.Anything).Return(article, nil)
+	bot := newTestBot(mockRapidApi, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("https://example.com/dead-article")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId})
+
+	_ = bot.handleOnText(mockContext)
+
+	mockContext.AssertCalled(t, "Send", "The article page couldn't be read, so the title is made up from the URL. Please make sure the link isn't dead or paywalled.\n\n"+
+		"Found the article:\nTitle: Dead Article\nAuthor: unknown\nLanguage: unknown\n\n"+
+		"Press \"Continue\" if this is the right article or send a corrected URL. To abort the operation type \"cancel\".", mock.Anything)
+}
+
+func TestOnTextHandler_WhenUrlStateAndExtractArticleFailed(t *testing.T) {
+	userId := int64(1017)
+	mockRapidApi := new(MockRapidAPIClient)
+	mockRapidApi.On("ExtractArticle", mock.Anything).Return(&extractor.Article{}, fmt.Errorf("extracting article error"))
+	bot := newTestBot(mockRapidApi, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("https://example.com/paywalled")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepUrl})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, UserArticleState{UserId: userId, Step: stepUrl}, actualState)
+	mockContext.AssertCalled(t, "Send", "Failed to fetch the article, please check the URL and send it again or abort the operation by typing \"cancel\".", mock.Anything)
+}
+
+func TestOnTextHandler_WhenArticleStateAndUrlCorrected(t *testing.T) {
+	userId := int64(1018)
+	mockRapidApi := new(MockRapidAPIClient)
+	article := &extractor.Article{Title: "Right Article", Url: "https://example.com/right"}
+	mockRapidApi.On("ExtractArticle", "https://example.com/right").Return(article, nil)
+	bot := newTestBot(mockRapidApi, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("https://example.com/right")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepArticle, Url: "https://example.com/wrong", Article: &extractor.Article{Title: "Wrong Article"}})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, UserArticleState{UserId: userId, Step: stepArticle, Url: "https://example.com/right", Article: article}, actualState)
+	mockContext.AssertCalled(t, "Send", "Found the article:\nTitle: Right Article\nAuthor: unknown\nLanguage: unknown\n\n"+
+		"Press \"Continue\" if this is the right article or send a corrected URL. To abort the operation type \"cancel\".", mock.Anything)
+}
+
+func TestContinueHandler(t *testing.T) {
+	userId := int64(1019)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	article := &extractor.Article{Title: "Article Title"}
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepArticle, Url: "https://example.com", Article: article})
+
+	_ = bot.handleContinue(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, UserArticleState{UserId: userId, Step: stepDescription, Url: "https://example.com", Article: article}, actualState)
 	mockContext.AssertCalled(t, "Send", "Step 2. Provide article description as a plain text. To abort the operation type \"cancel\".", mock.Anything)
 }
 
@@ -131,6 +303,7 @@ func TestOnTextHandler_WhenDescriptionState(t *testing.T) {
 
 	expectedState := UserArticleState{
 		UserId:      userId,
+		Step:        stepLevel,
 		Url:         "https://example.com",
 		Description: "Nice article.",
 	}
@@ -138,12 +311,14 @@ func TestOnTextHandler_WhenDescriptionState(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, expectedState, actualState)
 	// TODO: Cover keyboard with unit tests.
-	mockContext.AssertCalled(t, "Send", "Step 3. Provide level. To abort the operation type \"cancel\".", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "Step 3. Choose level below. To abort the operation type \"cancel\".", mock.Anything)
 }
 
 func TestOnTextHandler_WhenLevelState(t *testing.T) {
 	userId := int64(1003)
-	bot := newTestBot(nil, nil)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:kafka"}}, nil)
+	bot := newTestBot(nil, mockGitHub)
 	mockContext := new(MockTelegramBotContext)
 	mockContext.On("Text").Return("advanced")
 	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
@@ -154,6 +329,7 @@ func TestOnTextHandler_WhenLevelState(t *testing.T) {
 
 	expectedState := UserArticleState{
 		UserId:      userId,
+		Step:        stepTopics,
 		Url:         "https://example.com",
 		Description: "Nice article.",
 		Level:       "advanced",
@@ -161,24 +337,224 @@ func TestOnTextHandler_WhenLevelState(t *testing.T) {
 	actualState, ok := bot.stateStorage.Get(userId)
 	assert.True(t, ok)
 	assert.Equal(t, expectedState, actualState)
-	mockContext.AssertCalled(t, "Send", "Step 4. Provide topics as a comma separated list, e.g. \"streaming, storage-engine, kafka\" without quotes. To abort the operation type \"cancel\".", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "Step 4. Choose topics below or type new ones as a comma separated list, then press \"Done\". To abort the operation type \"cancel\".", mock.Anything)
+}
+
+func TestOnTextHandler_WhenLevelStateAndUnknownLevel(t *testing.T) {
+	userId := int64(1029)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("Advanced!!")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article."})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, "", actualState.Level)
+	assert.Equal(t, stepLevel, actualState.currentStep())
+	mockContext.AssertCalled(t, "Send", "Unknown level \"Advanced!!\", please choose one of the buttons below. To abort the operation type \"cancel\".", mock.Anything)
+}
+
+func TestLevelHandler(t *testing.T) {
+	userId := int64(1030)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:kafka"}}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Data").Return("Medium")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article."})
+
+	_ = bot.handleLevel(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, "medium", actualState.Level)
+	assert.Equal(t, stepTopics, actualState.Step)
+}
+
+func TestLevelHandler_WhenNotLevelStep(t *testing.T) {
+	userId := int64(1031)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Data").Return("beginner")
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	state := UserArticleState{UserId: userId, Step: stepPreview, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"kafka"}}
+	bot.stateStorage.Set(userId, state)
+
+	_ = bot.handleLevel(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, state, actualState)
+	mockContext.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestGetLevelKeyboard(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	bot.levels = []string{"intro", "beginner", "medium", "advanced"}
+
+	rows := bot.getLevelKeyboard().InlineKeyboard
+
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "intro", rows[0][0].Text)
+	assert.Equal(t, "intro", rows[0][0].Data)
+	assert.Equal(t, "advanced", rows[1][0].Text)
 }
 
-func TestOnTextHandler_WhenSuccessfullyCreateIssue(t *testing.T) {
+func TestOnTextHandler_WhenTopicsState(t *testing.T) {
 	userId := int64(1004)
-	mockRapidApi := new(MockRapidAPIClient)
-	mockRapidApi.On("ExtractArticle", mock.Anything).Return(&rapidapi.Article{Title: "Article Title", Author: "Noname Blog", Url: "https://example.com/1"}, nil)
 	mockGitHub := new(MockGitHubClient)
-	mockGitHub.On("CreateIssue", mock.Anything).Return("https://github.com/deordie/deordie-digest/issues/1", nil)
-	bot := newTestBot(mockRapidApi, mockGitHub)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:topic1"}, {Name: "topic:Topic2"}}, nil)
+	bot := newTestBot(nil, mockGitHub)
 	mockContext := new(MockTelegramBotContext)
 	mockContext.On("Text").Return("topic1, topic2")
 	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
 	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
-	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article.", Level: "advanced"})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepTopics, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"topic1"}})
+
+	_ = bot.handleOnText(mockContext)
+
+	expectedState := UserArticleState{
+		UserId:      userId,
+		Step:        stepTopics,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1", "Topic2"},
+	}
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, expectedState, actualState)
+	mockContext.AssertCalled(t, "Send", "Step 4. Choose topics below or type new ones as a comma separated list, then press \"Done\". To abort the operation type \"cancel\".\nSelected: topic1, Topic2", mock.Anything)
+}
+
+func TestTopicsDoneHandler(t *testing.T) {
+	userId := int64(1028)
+	mockRapidApi := new(MockRapidAPIClient)
+	article := &extractor.Article{Title: "Article Title", Author: "Noname Blog", Url: "https://example.com/1"}
+	mockRapidApi.On("ExtractArticle", mock.Anything).Return(article, nil)
+	bot := newTestBot(mockRapidApi, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepTopics, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"topic1", "topic2"}})
+
+	_ = bot.handleTopicsDone(mockContext)
+
+	expectedState := UserArticleState{
+		UserId:      userId,
+		Step:        stepPreview,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1", "topic2"},
+		Article:     article,
+	}
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, expectedState, actualState)
+	mockRapidApi.AssertCalled(t, "ExtractArticle", "https://example.com")
+	mockContext.AssertCalled(t, "Send", "Step 5. Review the GitHub issue before submitting.\n\n"+
+		"Title: Article Title / Noname Blog\n"+
+		"Labels: level:advanced, topic:topic1, topic:topic2\n\n"+
+		"__URL:__ https://example.com/1\n\n__Review (1-2 sentences):__ Nice article.\n\n__Created by:__ DE or DIE Bot :robot: on behalf of https://t.me/nickname.", mock.Anything)
+}
+
+func TestOnTextHandler_WhenTopicsStateWithUnknownTopics(t *testing.T) {
+	userId := int64(1026)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:storage-engine"}, {Name: "topic:Streaming"}, {Name: "level:advanced"}}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("Storage Engines, straming, spark, ")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Article:     &extractor.Article{Title: "Article Title", Url: "https://example.com"},
+	})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"storage-engine", "straming", "spark"}, actualState.Topics)
+	mockContext.AssertCalled(t, "Send", "\"straming\" is a new topic, did you mean: Streaming?\nUse /edit topics to change them.", mock.Anything)
+}
+
+func TestOnTextHandler_WhenTopicsStateAndLabelsUnavailable(t *testing.T) {
+	userId := int64(1027)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label(nil), fmt.Errorf("list labels error"))
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("kafka, streaming")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Article:     &extractor.Article{Title: "Article Title", Url: "https://example.com"},
+	})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"kafka", "streaming"}, actualState.Topics)
+	assert.Equal(t, stepTopics, actualState.Step)
+}
+
+func TestOnTextHandler_WhenPreviewState(t *testing.T) {
+	userId := int64(1007)
+	mockRapidApi := new(MockRapidAPIClient)
+	bot := newTestBot(mockRapidApi, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("some text")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	state := UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"topic1"}, Article: &extractor.Article{Title: "Article Title"}}
+	bot.stateStorage.Set(userId, state)
 
 	_ = bot.handleOnText(mockContext)
 
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, state, actualState)
+	mockRapidApi.AssertNotCalled(t, "ExtractArticle", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "Please use the buttons below the preview to submit the article, edit a field or cancel the operation.", mock.Anything)
+}
+
+func TestSubmitHandler_WhenSuccessfullyCreateIssue(t *testing.T) {
+	userId := int64(1008)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", mock.Anything).Return((*github.Issue)(nil), nil)
+	mockGitHub.On("CreateIssue", mock.Anything).Return("https://github.com/deordie/deordie-digest/issues/1", nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1", "topic2"},
+		Article:     &extractor.Article{Title: "Article Title", Author: "Noname Blog", Url: "https://example.com/1"},
+	})
+
+	_ = bot.handleSubmit(mockContext)
+
 	expectedArticleIssue := &github.ArticleIssue{
 		Url:         "https://example.com/1",
 		Title:       "Article Title",
@@ -190,23 +566,41 @@ func TestOnTextHandler_WhenSuccessfullyCreateIssue(t *testing.T) {
 	}
 	_, ok := bot.stateStorage.Get(userId)
 	assert.False(t, ok)
-	mockRapidApi.AssertCalled(t, "ExtractArticle", "https://example.com")
+	records := bot.history.Recent(userId, mySubmissionsLimit)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "https://example.com/1", records[0].Url)
+	assert.Equal(t, "Article Title", records[0].Title)
+	assert.Equal(t, "https://github.com/deordie/deordie-digest/issues/1", records[0].IssueUrl)
 	mockGitHub.AssertCalled(t, "CreateIssue", expectedArticleIssue)
 	mockContext.AssertCalled(t, "Send", "The article was added to the digest candidates! GitHub issue link: https://github.com/deordie/deordie-digest/issues/1", mock.Anything)
 }
 
-func TestOnTextHandler_WhenExtractArticleFailed(t *testing.T) {
+func TestSubmitHandler_WhenIncompleteDraft(t *testing.T) {
+	userId := int64(1009)
+	mockGitHub := new(MockGitHubClient)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com"})
+
+	_ = bot.handleSubmit(mockContext)
+
+	mockGitHub.AssertNotCalled(t, "CreateIssue", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "There is no article draft to submit. Start with /newarticle command.", mock.Anything)
+}
+
+func TestTopicsDoneHandler_WhenExtractArticleFailed(t *testing.T) {
 	userId := int64(1005)
 	mockRapidApi := new(MockRapidAPIClient)
-	mockRapidApi.On("ExtractArticle", mock.Anything).Return(&rapidapi.Article{}, fmt.Errorf("extracting article error"))
+	mockRapidApi.On("ExtractArticle", mock.Anything).Return(&extractor.Article{}, fmt.Errorf("extracting article error"))
 	bot := newTestBot(mockRapidApi, nil)
 	mockContext := new(MockTelegramBotContext)
-	mockContext.On("Text").Return("topic1, topic2")
 	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
 	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
-	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article.", Level: "advanced"})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepTopics, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"topic1", "topic2"}})
 
-	_ = bot.handleOnText(mockContext)
+	_ = bot.handleTopicsDone(mockContext)
 
 	_, ok := bot.stateStorage.Get(userId)
 	assert.False(t, ok)
@@ -214,20 +608,25 @@ func TestOnTextHandler_WhenExtractArticleFailed(t *testing.T) {
 	mockContext.AssertCalled(t, "Send", "Operation failed on fetching article.", mock.Anything)
 }
 
-func TestOnTextHandler_WhenCreateIssueFailed(t *testing.T) {
+func TestSubmitHandler_WhenCreateIssueFailed(t *testing.T) {
 	userId := int64(1006)
-	mockRapidApi := new(MockRapidAPIClient)
-	mockRapidApi.On("ExtractArticle", mock.Anything).Return(&rapidapi.Article{Title: "Article Title", Author: "Noname Blog", Url: "https://example.com/1"}, nil)
 	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", mock.Anything).Return((*github.Issue)(nil), nil)
 	mockGitHub.On("CreateIssue", mock.Anything).Return("", fmt.Errorf("create issue error"))
-	bot := newTestBot(mockRapidApi, mockGitHub)
+	bot := newTestBot(nil, mockGitHub)
 	mockContext := new(MockTelegramBotContext)
-	mockContext.On("Text").Return("topic1, topic2")
 	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
 	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
-	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article.", Level: "advanced"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1", "topic2"},
+		Article:     &extractor.Article{Title: "Article Title", Author: "Noname Blog", Url: "https://example.com/1"},
+	})
 
-	_ = bot.handleOnText(mockContext)
+	_ = bot.handleSubmit(mockContext)
 
 	expectedArticleIssue := &github.ArticleIssue{
 		Url:         "https://example.com/1",
@@ -240,11 +639,97 @@ func TestOnTextHandler_WhenCreateIssueFailed(t *testing.T) {
 	}
 	_, ok := bot.stateStorage.Get(userId)
 	assert.False(t, ok)
-	mockRapidApi.AssertCalled(t, "ExtractArticle", "https://example.com")
 	mockGitHub.AssertCalled(t, "CreateIssue", expectedArticleIssue)
 	mockContext.AssertCalled(t, "Send", "Operation failed on creating GitHub issue.", mock.Anything)
 }
 
+func TestSubmitHandler_WhenRateLimitExceeded(t *testing.T) {
+	userId := int64(1023)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", mock.Anything).Return((*github.Issue)(nil), nil)
+	rateLimitErr := &github.RateLimitError{Operation: "CreateIssue", ResetAt: time.Now().Add(2 * time.Hour)}
+	mockGitHub.On("CreateIssue", mock.Anything).Return("", &retry.TemporaryError{Attempts: 1, Err: rateLimitErr})
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1"},
+		Article:     &extractor.Article{Title: "Article Title", Url: "https://example.com"},
+	})
+
+	_ = bot.handleSubmit(mockContext)
+
+	queued := bot.outbox.Items()
+	assert.Len(t, queued, 1, "expected the submission to be queued")
+	for _, submission := range queued {
+		assert.Equal(t, userId, submission.UserId)
+		assert.Equal(t, 1, submission.Attempts)
+		assert.Equal(t, rateLimitErr.ResetAt, submission.NextAttemptAt, "expected no retries before the rate limit resets")
+	}
+	mockContext.AssertCalled(t, "Send", "GitHub is temporarily unavailable, so your article is queued. You will get the GitHub issue link here once it's submitted.", mock.Anything)
+}
+
+func TestSubmitHandler_WhenGitHubTemporarilyUnavailable(t *testing.T) {
+	userId := int64(1024)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", mock.Anything).Return((*github.Issue)(nil), nil)
+	mockGitHub.On("CreateIssue", mock.Anything).Return("", &retry.TemporaryError{Attempts: 3, Err: fmt.Errorf("non-successful HTTP status code in CreateIssue call: 502")})
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1"},
+		Article:     &extractor.Article{Title: "Article Title", Url: "https://example.com"},
+	})
+
+	_ = bot.handleSubmit(mockContext)
+
+	_, ok := bot.stateStorage.Get(userId)
+	assert.False(t, ok, "expected the draft to be moved to the outbox")
+	assert.Len(t, bot.outbox.Items(), 1, "expected the submission to be queued")
+	mockContext.AssertCalled(t, "Send", "GitHub is temporarily unavailable, so your article is queued. You will get the GitHub issue link here once it's submitted.", mock.Anything)
+}
+
+func TestSubmitHandler_WhenGitHubRejectedIssue(t *testing.T) {
+	userId := int64(1025)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", mock.Anything).Return((*github.Issue)(nil), nil)
+	apiErr := &github.APIError{
+		Operation:  "CreateIssue",
+		StatusCode: 422,
+		Message:    "Validation Failed",
+		Errors:     []github.FieldError{{Resource: "Label", Field: "name", Code: "invalid"}},
+	}
+	mockGitHub.On("CreateIssue", mock.Anything).Return("", apiErr)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1"},
+		Article:     &extractor.Article{Title: "Article Title", Url: "https://example.com"},
+	})
+
+	_ = bot.handleSubmit(mockContext)
+
+	assert.Empty(t, bot.outbox.Items(), "expected the rejected submission not to be queued")
+	mockContext.AssertCalled(t, "Send", "Operation failed on creating GitHub issue. GitHub rejected it: Validation Failed: name invalid.", mock.Anything)
+}
+
 func TestOnTextHandler_WhenEmptyState(t *testing.T) {
 	userId := int64(8888)
 	bot := newTestBot(nil, nil)
@@ -273,3 +758,258 @@ func TestOnTextHandler_WhenCancelled(t *testing.T) {
 	assert.Nil(t, err)
 	mockContext.AssertCalled(t, "Send", "The operation was cancelled.", mock.Anything)
 }
+
+func TestDraftExpiredHandler(t *testing.T) {
+	userId := int64(7777)
+	bot := newTestBot(nil, nil)
+
+	bot.handleDraftExpired(userId, UserArticleState{UserId: userId, Url: "https://example.com"})
+
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "Your article draft has expired due to inactivity. Start over with /newarticle command.", mock.Anything)
+}
+
+func TestEditFieldHandler(t *testing.T) {
+	userId := int64(1010)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Data").Return("description")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"topic1"}, Article: &extractor.Article{Title: "Article Title"}})
+
+	_ = bot.handleEditField(mockContext)
+
+	expectedState := UserArticleState{
+		UserId:      userId,
+		Step:        stepDescription,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1"},
+		Article:     &extractor.Article{Title: "Article Title"},
+	}
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, expectedState, actualState)
+	mockContext.AssertCalled(t, "Send", "Step 2. Provide article description as a plain text. To abort the operation type \"cancel\".\nCurrent value: Nice article.", mock.Anything)
+}
+
+func TestOnTextHandler_WhenEditedFieldProvided(t *testing.T) {
+	userId := int64(1011)
+	mockRapidApi := new(MockRapidAPIClient)
+	bot := newTestBot(mockRapidApi, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("Even nicer article.")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepDescription, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"topic1"}, Article: &extractor.Article{Title: "Article Title", Url: "https://example.com"}})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, "Even nicer article.", actualState.Description)
+	assert.Equal(t, stepPreview, actualState.Step)
+	mockRapidApi.AssertNotCalled(t, "ExtractArticle", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "Step 5. Review the GitHub issue before submitting.\n\n"+
+		"Title: Article Title\n"+
+		"Labels: level:advanced, topic:topic1\n\n"+
+		"__URL:__ https://example.com\n\n__Review (1-2 sentences):__ Even nicer article.\n\n__Created by:__ DE or DIE Bot :robot: on behalf of https://t.me/nickname.", mock.Anything)
+}
+
+func TestCancelHandler(t *testing.T) {
+	userId := int64(1012)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com"})
+
+	_ = bot.handleCancel(mockContext)
+
+	_, ok := bot.stateStorage.Get(userId)
+	assert.False(t, ok)
+	mockContext.AssertCalled(t, "Send", "The operation was cancelled.", mock.Anything)
+}
+
+func TestEditCommandHandler(t *testing.T) {
+	userId := int64(1013)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:topic1"}}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Args").Return([]string{"Topics"})
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepPreview, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"topic1", "topic2"}})
+
+	_ = bot.handleEditCommand(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, stepTopics, actualState.Step)
+	assert.Equal(t, []string{"topic1", "topic2"}, actualState.Topics)
+	mockContext.AssertCalled(t, "Send", "Step 4. Choose topics below or type new ones as a comma separated list, then press \"Done\". To abort the operation type \"cancel\".\nSelected: topic1, topic2", mock.Anything)
+}
+
+func TestEditCommandHandler_WhenUnknownField(t *testing.T) {
+	userId := int64(1014)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Args").Return([]string{"author"})
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepPreview, Url: "https://example.com"})
+
+	_ = bot.handleEditCommand(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, stepPreview, actualState.Step)
+	mockContext.AssertCalled(t, "Send", "Unknown field, please choose one of the buttons.", mock.Anything)
+}
+
+func TestBackHandler(t *testing.T) {
+	userId := int64(1015)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepLevel, Url: "https://example.com", Description: "Nice article."})
+
+	_ = bot.handleBack(mockContext)
+
+	expectedState := UserArticleState{
+		UserId:      userId,
+		Step:        stepDescription,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+	}
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, expectedState, actualState)
+	mockContext.AssertCalled(t, "Send", "Step 2. Provide article description as a plain text. To abort the operation type \"cancel\".\nCurrent value: Nice article.", mock.Anything)
+}
+
+func TestBackHandler_WhenLegacyDraftWithoutArticle(t *testing.T) {
+	userId := int64(1099)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepDescription, Url: "https://example.com"})
+
+	_ = bot.handleBack(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, stepUrl, actualState.Step)
+	mockContext.AssertCalled(t, "Send", "Step 1. Provide article URL. To abort the operation type \"cancel\".\nCurrent value: https://example.com", mock.Anything)
+}
+
+func TestBackHandler_WhenFirstStep(t *testing.T) {
+	userId := int64(1016)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepUrl})
+
+	_ = bot.handleBack(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, stepUrl, actualState.Step)
+	mockContext.AssertCalled(t, "Send", "You are already at the first step.", mock.Anything)
+}
+
+func TestSubmitHandler_WhenDuplicateIssueExists(t *testing.T) {
+	userId := int64(1020)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", mock.Anything).Return(&github.Issue{Number: 42, HtmlUrl: "https://github.com/deordie/deordie-digest/issues/42"}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	state := UserArticleState{
+		UserId:      userId,
+		Step:        stepPreview,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1"},
+		Article:     &extractor.Article{Title: "Article Title", Url: "https://example.com/1"},
+	}
+	bot.stateStorage.Set(userId, state)
+
+	_ = bot.handleSubmit(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, state, actualState)
+	mockGitHub.AssertCalled(t, "FindIssueByUrl", "https://example.com/1")
+	mockGitHub.AssertNotCalled(t, "CreateIssue", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "This article is already a digest candidate: https://github.com/deordie/deordie-digest/issues/42\n"+
+		"Do you want to add your review there as a comment instead?", mock.Anything)
+}
+
+func TestAddCommentHandler(t *testing.T) {
+	userId := int64(1021)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("AddComment", mock.Anything, mock.Anything).Return("https://github.com/deordie/deordie-digest/issues/42#issuecomment-1", nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Data").Return("42")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Step:        stepPreview,
+		Url:         "https://example.com",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1"},
+		Article:     &extractor.Article{Title: "Article Title", Url: "https://example.com/1"},
+	})
+
+	_ = bot.handleAddComment(mockContext)
+
+	expectedArticleIssue := &github.ArticleIssue{
+		Url:         "https://example.com/1",
+		Title:       "Article Title",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"topic1"},
+		User:        "https://t.me/nickname",
+	}
+	_, ok := bot.stateStorage.Get(userId)
+	assert.False(t, ok)
+	mockGitHub.AssertCalled(t, "AddComment", 42, expectedArticleIssue)
+	mockContext.AssertCalled(t, "Send", "Your review was added to the existing digest candidate! GitHub comment link: https://github.com/deordie/deordie-digest/issues/42#issuecomment-1", mock.Anything)
+}
+
+func TestOnTextHandler_WhenUrlStateWithTrackingParameters(t *testing.T) {
+	userId := int64(1022)
+	mockRapidApi := new(MockRapidAPIClient)
+	article := &extractor.Article{
+		Title:        "Article Title",
+		Url:          "https://example.com/article?utm_source=telegram",
+		EffectiveUrl: "https://example.com/article/?fbclid=abc",
+	}
+	mockRapidApi.On("ExtractArticle", mock.Anything).Return(article, nil)
+	bot := newTestBot(mockRapidApi, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("https://example.com/article?utm_source=telegram")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepUrl})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com/article", actualState.Url)
+	assert.Equal(t, "https://example.com/article", actualState.Article.EffectiveUrl)
+	mockRapidApi.AssertCalled(t, "ExtractArticle", "https://example.com/article?utm_source=telegram")
+	assert.Equal(t, "https://example.com/article", newArticleIssue("nickname", actualState.Article, &actualState).Url)
+}
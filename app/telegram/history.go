@@ -0,0 +1,126 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/storage"
+	tele "gopkg.in/telebot.v3"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	mySubmissionsCommand = "/mysubmissions"
+
+	maxHistoryRecords = 50
+	// mySubmissionsLimit is how many recent submissions are listed, each of them costs a GitHub call.
+	mySubmissionsLimit = 10
+)
+
+type SubmissionRecord struct {
+	UserId      int64     `json:"user_id"`
+	Url         string    `json:"url"`
+	Title       string    `json:"title"`
+	IssueUrl    string    `json:"issue_url"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+type History struct {
+	storage.Storage[[]SubmissionRecord]
+	mutex sync.Mutex
+}
+
+func NewHistory(s storage.Storage[[]SubmissionRecord]) *History {
+	return &History{
+		Storage: s,
+	}
+}
+
+func (h *History) Add(record SubmissionRecord) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	records, _ := h.Get(record.UserId)
+	// Copy the records, so the slice held by the in-memory storage is never changed in place.
+	updated := make([]SubmissionRecord, 0, len(records)+1)
+	updated = append(updated, records...)
+	updated = append(updated, record)
+	if len(updated) > maxHistoryRecords {
+		updated = updated[len(updated)-maxHistoryRecords:]
+	}
+	h.Set(record.UserId, updated)
+}
+
+func (h *History) Recent(userId int64, limit int) []SubmissionRecord {
+	records, _ := h.Get(userId)
+	recent := make([]SubmissionRecord, 0, min(len(records), limit))
+	for i := len(records) - 1; i >= 0 && len(recent) < limit; i-- {
+		recent = append(recent, records[i])
+	}
+	return recent
+}
+
+func (b *Bot) handleMySubmissions(ctx tele.Context) error {
+	m := b.messages(ctx)
+	records := b.history.Recent(ctx.Sender().ID, mySubmissionsLimit)
+	if len(records) == 0 {
+		return ctx.Send(fmt.Sprintf(m.NoSubmissions, newArticleCommand), tele.RemoveKeyboard)
+	}
+
+	callCtx, cancel := b.newCallContext(requestContext(ctx))
+	defer cancel()
+	issues := b.lookupIssues(callCtx, records)
+
+	items := make([]string, 0, len(records)+1)
+	items = append(items, m.MySubmissions)
+	for i, record := range records {
+		title := record.Title
+		if title == "" {
+			title = record.Url
+		}
+		submittedAt := record.SubmittedAt.UTC().Format("2006-01-02")
+		items = append(items, fmt.Sprintf(m.SubmissionItem, title, submittedAt, record.IssueUrl, formatIssueState(m, issues[i])))
+	}
+	return ctx.Send(strings.Join(items, "\n\n"), tele.NoPreview, tele.RemoveKeyboard)
+}
+
+func (b *Bot) lookupIssues(ctx context.Context, records []SubmissionRecord) []*github.Issue {
+	issues := make([]*github.Issue, len(records))
+	var wg sync.WaitGroup
+	for i, record := range records {
+		issueNumber, ok := github.IssueNumber(record.IssueUrl)
+		if !ok {
+			slog.WarnContext(ctx, "Invalid GitHub issue URL in the submission history", "issue_url", record.IssueUrl, "user_id", record.UserId)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, issueNumber int) {
+			defer wg.Done()
+			iss, err := b.githubIssueGetter.GetIssue(ctx, issueNumber)
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to get GitHub issue", "issue_number", issueNumber, "error", err)
+				return
+			}
+			issues[i] = iss
+		}(i, issueNumber)
+	}
+	wg.Wait()
+	return issues
+}
+
+func formatIssueState(m *Messages, iss *github.Issue) string {
+	switch {
+	case iss == nil:
+		return m.IssueStateUnknown
+	case iss.InDigest():
+		return fmt.Sprintf(m.IssueInDigest, iss.Milestone.Title)
+	case iss.State == github.IssueStateClosed:
+		return m.IssueClosed
+	default:
+		return m.IssueOpen
+	}
+}
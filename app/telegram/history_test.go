@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	tele "gopkg.in/telebot.v3"
+	"testing"
+	"time"
+)
+
+func TestHistory_Recent(t *testing.T) {
+	history := NewHistory(storage.NewInMemoryStorage[[]SubmissionRecord]())
+	for i := 1; i <= maxHistoryRecords+2; i++ {
+		history.Add(SubmissionRecord{UserId: 5000, Url: fmt.Sprintf("https://example.com/%d", i)})
+	}
+	history.Add(SubmissionRecord{UserId: 5001, Url: "https://example.com/other"})
+
+	records, _ := history.Get(5000)
+	recent := history.Recent(5000, 2)
+
+	assert.Len(t, records, maxHistoryRecords)
+	assert.Equal(t, "https://example.com/3", records[0].Url)
+	assert.Equal(t, []SubmissionRecord{
+		{UserId: 5000, Url: fmt.Sprintf("https://example.com/%d", maxHistoryRecords+2)},
+		{UserId: 5000, Url: fmt.Sprintf("https://example.com/%d", maxHistoryRecords+1)},
+	}, recent)
+	assert.Empty(t, history.Recent(5002, 2))
+}
+
+func TestMySubmissionsHandler(t *testing.T) {
+	userId := int64(5003)
+	submittedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("GetIssue", 1).Return(&github.Issue{Number: 1, State: github.IssueStateOpen}, nil)
+	mockGitHub.On("GetIssue", 2).Return(&github.Issue{Number: 2, State: github.IssueStateClosed}, nil)
+	mockGitHub.On("GetIssue", 3).Return(&github.Issue{Number: 3, State: github.IssueStateClosed, Milestone: &github.Milestone{Title: "#42"}}, nil)
+	mockGitHub.On("GetIssue", 4).Return((*github.Issue)(nil), errors.New("get issue error"))
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	for i, title := range []string{"First", "Second", "Third", ""} {
+		bot.history.Add(SubmissionRecord{
+			UserId:      userId,
+			Url:         "https://example.com/4",
+			Title:       title,
+			IssueUrl:    fmt.Sprintf("https://github.com/owner/repo/issues/%d", i+1),
+			SubmittedAt: submittedAt,
+		})
+	}
+
+	_ = bot.handleMySubmissions(mockContext)
+
+	mockContext.AssertCalled(t, "Send", "Your recent proposals:\n\n"+
+		"https://example.com/4\nSubmitted: 2026-10-01\nGitHub issue: https://github.com/owner/repo/issues/4\nState: unknown, GitHub is unavailable\n\n"+
+		"Third\nSubmitted: 2026-10-01\nGitHub issue: https://github.com/owner/repo/issues/3\nState: included in the digest #42\n\n"+
+		"Second\nSubmitted: 2026-10-01\nGitHub issue: https://github.com/owner/repo/issues/2\nState: closed\n\n"+
+		"First\nSubmitted: 2026-10-01\nGitHub issue: https://github.com/owner/repo/issues/1\nState: open", mock.Anything)
+}
+
+func TestMySubmissionsHandler_WhenNoSubmissions(t *testing.T) {
+	mockGitHub := new(MockGitHubClient)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: 5004})
+
+	_ = bot.handleMySubmissions(mockContext)
+
+	mockGitHub.AssertNotCalled(t, "GetIssue", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "You haven't proposed any articles yet. Start with /newarticle command.", mock.Anything)
+}
+
+func TestDrainOutbox_RecordsSubmission(t *testing.T) {
+	userId := int64(5005)
+	articleIssue := &github.ArticleIssue{Url: "https://example.com", Title: "Article Title"}
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", articleIssue.Url).Return((*github.Issue)(nil), nil)
+	mockGitHub.On("CreateIssue", articleIssue).Return("https://github.com/owner/repo/issues/1", nil)
+	bot := newTestBot(nil, mockGitHub)
+	bot.outbox.Add(Submission{UserId: userId, Issue: articleIssue, Attempts: 1})
+
+	bot.drainOutbox(context.Background())
+
+	records := bot.history.Recent(userId, mySubmissionsLimit)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "https://github.com/owner/repo/issues/1", records[0].IssueUrl)
+}
@@ -0,0 +1,82 @@
+package telegram
+
+import (
+	tele "gopkg.in/telebot.v3"
+	"sort"
+	"strings"
+)
+
+const (
+	languageCommand = "/language"
+	languageButton  = "language"
+
+	DefaultLanguage = "en"
+)
+
+type Preferences struct {
+	Language string `json:"language"`
+}
+
+func DefaultCatalogs() map[string]*Messages {
+	return map[string]*Messages{
+		"en": DefaultMessages(),
+		"ru": RussianMessages(),
+	}
+}
+
+func (b *Bot) language(userId int64, languageCode string) string {
+	if preferences, ok := b.preferences.Get(userId); ok && b.catalogs[preferences.Language] != nil {
+		return preferences.Language
+	}
+
+	// Telegram reports IETF language tags, e.g. "en-US", while the catalogs are keyed by the language only.
+	language, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	if b.catalogs[language] != nil {
+		return language
+	}
+	return DefaultLanguage
+}
+
+func (b *Bot) messages(ctx tele.Context) *Messages {
+	user := ctx.Sender()
+	return b.catalogs[b.language(user.ID, user.LanguageCode)]
+}
+
+func (b *Bot) handleLanguage(ctx tele.Context) error {
+	args := ctx.Args()
+	if len(args) == 0 {
+		return ctx.Send(b.messages(ctx).ChooseLanguage, b.getLanguageKeyboard())
+	}
+	return b.setLanguage(ctx, strings.ToLower(args[0]))
+}
+
+func (b *Bot) handleLanguageButton(ctx tele.Context) error {
+	_ = ctx.Respond()
+	return b.setLanguage(ctx, ctx.Data())
+}
+
+func (b *Bot) setLanguage(ctx tele.Context, language string) error {
+	m, ok := b.catalogs[language]
+	if !ok {
+		return ctx.Send(b.messages(ctx).UnknownLanguage, b.getLanguageKeyboard())
+	}
+
+	b.preferences.Set(ctx.Sender().ID, Preferences{Language: language})
+	return ctx.Send(m.LanguageChanged)
+}
+
+func (b *Bot) getLanguageKeyboard() *tele.ReplyMarkup {
+	languages := make([]string, 0, len(b.catalogs))
+	for language := range b.catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	keyboard := &tele.ReplyMarkup{}
+	row := make(tele.Row, 0, len(languages))
+	for _, language := range languages {
+		row = append(row, keyboard.Data(b.catalogs[language].LanguageName, languageButton, language))
+	}
+	keyboard.Inline(row)
+	return keyboard
+}
@@ -0,0 +1,139 @@
+package telegram
+
+import (
+	"context"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	tele "gopkg.in/telebot.v3"
+	"testing"
+)
+
+func TestRussianMessages_Validate(t *testing.T) {
+	err := RussianMessages().Validate()
+
+	assert.NoError(t, err)
+}
+
+func TestStartHandler_WhenRussianTelegramLanguage(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: 4000, LanguageCode: "ru-RU"})
+
+	_ = bot.handleStart(mockContext)
+
+	mockContext.AssertCalled(t, "Send", "Привет! Я помогу быстро предложить статью для DE or DIE: Digest. Начните с команды /newarticle и следуйте инструкциям.", mock.Anything)
+}
+
+func TestStartHandler_WhenUnsupportedTelegramLanguage(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: 4001, LanguageCode: "de"})
+
+	_ = bot.handleStart(mockContext)
+
+	mockContext.AssertCalled(t, "Send", "Hello! I can help you to quickly propose an article for DE or DIE: Digest. Start with /newarticle command and follow the instructions.", mock.Anything)
+}
+
+func TestLanguageHandler(t *testing.T) {
+	userId := int64(4002)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Args").Return([]string{"RU"})
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, LanguageCode: "en"})
+
+	_ = bot.handleLanguage(mockContext)
+	_ = bot.handleStart(mockContext)
+
+	preferences, ok := bot.preferences.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, "ru", preferences.Language)
+	mockContext.AssertCalled(t, "Send", "Готово, теперь бот говорит по-русски.", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "Привет! Я помогу быстро предложить статью для DE or DIE: Digest. Начните с команды /newarticle и следуйте инструкциям.", mock.Anything)
+}
+
+func TestLanguageHandler_WithoutArgs(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Args").Return([]string{})
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: 4003})
+
+	_ = bot.handleLanguage(mockContext)
+
+	mockContext.AssertCalled(t, "Send", "Choose the language of the bot:", mock.Anything)
+}
+
+func TestLanguageButtonHandler_WhenUnknownLanguage(t *testing.T) {
+	userId := int64(4004)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Data").Return("de")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+
+	_ = bot.handleLanguageButton(mockContext)
+
+	_, ok := bot.preferences.Get(userId)
+	assert.False(t, ok)
+	mockContext.AssertCalled(t, "Send", "Unknown language, please choose one of the buttons.", mock.Anything)
+}
+
+func TestGetLanguageKeyboard(t *testing.T) {
+	bot := newTestBot(nil, nil)
+
+	rows := bot.getLanguageKeyboard().InlineKeyboard
+
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "English", rows[0][0].Text)
+	assert.Equal(t, "en", rows[0][0].Data)
+	assert.Equal(t, "Русский", rows[0][1].Text)
+}
+
+func TestOnTextHandler_WhenRussianCancelKeyword(t *testing.T) {
+	userId := int64(4005)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("Отмена")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, LanguageCode: "ru"})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Url: "https://example.com"})
+
+	_ = bot.handleOnText(mockContext)
+
+	_, ok := bot.stateStorage.Get(userId)
+	assert.False(t, ok)
+	mockContext.AssertCalled(t, "Send", "Операция отменена.", mock.Anything)
+}
+
+func TestNewArticleHandler_WhenCustomCancelKeyword(t *testing.T) {
+	userId := int64(4007)
+	bot := newTestBot(nil, nil)
+	messages := RussianMessages()
+	messages.CancelKeyword = "стоп"
+	bot.catalogs["ru"] = messages
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, LanguageCode: "ru"})
+
+	_ = bot.handleNewArticle(mockContext)
+
+	mockContext.AssertCalled(t, "Send", "Шаг 1. Пришлите ссылку на статью. Чтобы прервать операцию, напишите \"стоп\".", mock.Anything)
+}
+
+func TestDrainOutbox_NotifiesInSubmissionLanguage(t *testing.T) {
+	userId := int64(4006)
+	articleIssue := &github.ArticleIssue{Url: "https://example.com", Title: "Article Title"}
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", articleIssue.Url).Return((*github.Issue)(nil), nil)
+	mockGitHub.On("CreateIssue", articleIssue).Return("https://github.com/owner/repo/issues/1", nil)
+	bot := newTestBot(nil, mockGitHub)
+	bot.outbox.Add(Submission{UserId: userId, Issue: articleIssue, Language: "ru", Attempts: 1})
+
+	bot.drainOutbox(context.Background())
+
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "Ваша статья из очереди добавлена в кандидаты дайджеста! Ссылка на GitHub issue: https://github.com/owner/repo/issues/1", mock.Anything)
+}
@@ -0,0 +1,60 @@
+package telegram
+
+import (
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+)
+
+const (
+	levelButton = "level"
+
+	levelsPerRow = 3
+)
+
+func (b *Bot) handleLevel(ctx tele.Context) error {
+	_ = ctx.Respond()
+
+	state, ok := b.stateStorage.Get(ctx.Sender().ID)
+	if !ok || state.currentStep() != stepLevel {
+		return nil
+	}
+	return b.setLevel(ctx, &state, ctx.Data())
+}
+
+func (b *Bot) setLevel(ctx tele.Context, state *UserArticleState, value string) error {
+	m := b.messages(ctx)
+	level, ok := b.matchLevel(value)
+	if !ok {
+		return ctx.Send(fmt.Sprintf(m.UnknownLevel, value, m.CancelKeyword), b.getLevelKeyboard())
+	}
+
+	state.Level = level
+	state.Step = state.nextStep()
+	b.stateStorage.Set(state.UserId, *state)
+	return b.promptStep(ctx, state)
+}
+
+func (b *Bot) matchLevel(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, level := range b.levels {
+		if strings.EqualFold(level, value) {
+			return level, true
+		}
+	}
+	return "", false
+}
+
+func (b *Bot) getLevelKeyboard() *tele.ReplyMarkup {
+	keyboard := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for i := 0; i < len(b.levels); i += levelsPerRow {
+		var row tele.Row
+		for _, level := range b.levels[i:min(i+levelsPerRow, len(b.levels))] {
+			row = append(row, keyboard.Data(level, levelButton, level))
+		}
+		rows = append(rows, row)
+	}
+	keyboard.Inline(rows...)
+	return keyboard
+}
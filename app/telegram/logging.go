@@ -0,0 +1,32 @@
+package telegram
+
+import (
+	"context"
+	"github.com/deordie/deordie-bot/app/logging"
+	tele "gopkg.in/telebot.v3"
+	"log/slog"
+)
+
+const correlationIdKey = "correlation_id"
+
+func correlate(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(ctx tele.Context) error {
+		ctx.Set(correlationIdKey, logging.NewCorrelationId())
+		slog.DebugContext(requestContext(ctx), "Handling Telegram update", "update_id", ctx.Update().ID)
+		return next(ctx)
+	}
+}
+
+func requestContext(ctx tele.Context) context.Context {
+	id, _ := ctx.Get(correlationIdKey).(string)
+	return logging.WithCorrelationId(context.Background(), id)
+}
+
+// handleError logs the errors returned by the handlers, telebot would print them with the standard logger.
+func handleError(err error, ctx tele.Context) {
+	if ctx == nil {
+		slog.Error("Telegram bot failed", "error", err)
+		return
+	}
+	slog.ErrorContext(requestContext(ctx), "Failed to handle Telegram update", "update_id", ctx.Update().ID, "error", err)
+}
@@ -0,0 +1,48 @@
+package telegram
+
+import (
+	"context"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/logging"
+	"github.com/stretchr/testify/assert"
+	tele "gopkg.in/telebot.v3"
+	"testing"
+)
+
+// contextRecordingCreator keeps the context of the CreateIssue call.
+type contextRecordingCreator struct {
+	ctx context.Context
+}
+
+func (c *contextRecordingCreator) CreateIssue(ctx context.Context, _ *github.ArticleIssue) (string, error) {
+	c.ctx = ctx
+	return "https://github.com/owner/repo/issues/1", nil
+}
+
+func TestCorrelate_GivesEveryUpdateItsId(t *testing.T) {
+	telebot := &tele.Bot{}
+	var ids []string
+	handler := correlate(func(ctx tele.Context) error {
+		ids = append(ids, logging.CorrelationId(requestContext(ctx)))
+		return nil
+	})
+
+	_ = handler(telebot.NewContext(tele.Update{ID: 1}))
+	_ = handler(telebot.NewContext(tele.Update{ID: 2}))
+
+	assert.Len(t, ids, 2)
+	assert.Len(t, ids[0], 16)
+	assert.NotEqual(t, ids[0], ids[1])
+}
+
+func TestSubmit_CarriesCorrelationIdIntoGitHubCall(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	creator := &contextRecordingCreator{}
+	bot.githubIssueCreator = creator
+	submission := Submission{UserId: 8000, Issue: &github.ArticleIssue{Url: "https://example.com"}, CorrelationId: "0123456789abcdef"}
+
+	_, _, err := bot.submit(bot.outbox.Add(submission), submission, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789abcdef", logging.CorrelationId(creator.ctx))
+}
@@ -0,0 +1,218 @@
+package telegram
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+)
+
+// Messages are all texts the bot sends in one language, so they can be translated and reworded in the config file.
+// The "%s" placeholders of a message are filled in the order given in its comment and must be kept when
+// the message is changed.
+type Messages struct {
+	// LanguageName is the name of the language in the language itself, it's shown on the language button.
+	LanguageName string `yaml:"language_name"`
+	// CancelKeyword aborts the draft when typed, besides "cancel".
+	CancelKeyword string `yaml:"cancel_keyword"`
+	// Start has the new article command.
+	Start string `yaml:"start"`
+	// Help has the new article, back, edit, my submissions and language commands.
+	Help string `yaml:"help"`
+	// DraftExpired has the new article command.
+	DraftExpired string `yaml:"draft_expired"`
+	Cancelled    string `yaml:"cancelled"`
+	// NoDraftToEdit and NoDraftToSubmit have the new article command.
+	NoDraftToEdit   string `yaml:"no_draft_to_edit"`
+	NoDraftToSubmit string `yaml:"no_draft_to_submit"`
+	FirstStep       string `yaml:"first_step"`
+	ChooseLanguage  string `yaml:"choose_language"`
+	LanguageChanged string `yaml:"language_changed"`
+	UnknownLanguage string `yaml:"unknown_language"`
+
+	MySubmissions string `yaml:"my_submissions"`
+	// NoSubmissions has the new article command.
+	NoSubmissions string `yaml:"no_submissions"`
+	// SubmissionItem has the article title, the submission date, the issue URL and the issue state.
+	SubmissionItem string `yaml:"submission_item"`
+	IssueOpen      string `yaml:"issue_open"`
+	IssueClosed    string `yaml:"issue_closed"`
+	// IssueInDigest has the title of the digest milestone.
+	IssueInDigest     string `yaml:"issue_in_digest"`
+	IssueStateUnknown string `yaml:"issue_state_unknown"`
+	// ArticleClosed has the issue title and URL.
+	ArticleClosed string `yaml:"article_closed"`
+	// ArticleLabeled has the issue title, the label and the issue URL.
+	ArticleLabeled string `yaml:"article_labeled"`
+	// ArticleInDigest has the issue title, the title of the digest milestone and the issue URL.
+	ArticleInDigest string `yaml:"article_in_digest"`
+
+	// UrlPrompt, InvalidUrl and ExtractFailed have the cancel keyword.
+	UrlPrompt     string `yaml:"url_prompt"`
+	InvalidUrl    string `yaml:"invalid_url"`
+	ExtractFailed string `yaml:"extract_failed"`
+	FetchFailed   string `yaml:"fetch_failed"`
+	// ArticleFound has the title, author and language of the article and the cancel keyword.
+	ArticleFound string `yaml:"article_found"`
+	TitleGuessed string `yaml:"title_guessed"`
+	UnknownValue string `yaml:"unknown_value"`
+	// DescriptionPrompt and LevelPrompt have the cancel keyword.
+	DescriptionPrompt string `yaml:"description_prompt"`
+	LevelPrompt       string `yaml:"level_prompt"`
+	// UnknownLevel has the typed level and the cancel keyword.
+	UnknownLevel string `yaml:"unknown_level"`
+	// TopicsPrompt has the cancel keyword.
+	TopicsPrompt string `yaml:"topics_prompt"`
+	// AllowedTopicsPrompt replaces the topics prompt when only the configured or the existing topics are allowed,
+	// it has the cancel keyword.
+	AllowedTopicsPrompt string `yaml:"allowed_topics_prompt"`
+	// SelectedTopics has the comma separated topics, it's appended to the topics prompt.
+	SelectedTopics string `yaml:"selected_topics"`
+	NoTopicsChosen string `yaml:"no_topics_chosen"`
+	// NewTopic has the typed topic and the closest existing topics.
+	NewTopic string `yaml:"new_topic"`
+	// TopicNotAllowed has the typed topic.
+	TopicNotAllowed string `yaml:"topic_not_allowed"`
+	// TopicNotAllowedSuggestion has the typed topic and the closest allowed topics.
+	TopicNotAllowedSuggestion string `yaml:"topic_not_allowed_suggestion"`
+	// EditTopicsHint has the edit command, it's appended to the topic suggestions.
+	EditTopicsHint string `yaml:"edit_topics_hint"`
+	// CurrentValue has the current value of the field, it's appended to the prompt of a reopened field.
+	CurrentValue string `yaml:"current_value"`
+	// Preview has the issue title, labels and body.
+	Preview           string `yaml:"preview"`
+	UsePreviewButtons string `yaml:"use_preview_buttons"`
+	ChooseField       string `yaml:"choose_field"`
+	UnknownField      string `yaml:"unknown_field"`
+
+	// Duplicate has the URL of the existing issue.
+	Duplicate string `yaml:"duplicate"`
+	// IssueCreated and QueuedIssueCreated have the issue URL.
+	IssueCreated       string `yaml:"issue_created"`
+	QueuedIssueCreated string `yaml:"queued_issue_created"`
+	Queued             string `yaml:"queued"`
+	// CreateIssueFailed and QueuedIssueFailed have the rejection text, which is empty when GitHub gave no reason.
+	CreateIssueFailed string `yaml:"create_issue_failed"`
+	QueuedIssueFailed string `yaml:"queued_issue_failed"`
+	// CommentAdded has the comment URL.
+	CommentAdded string `yaml:"comment_added"`
+	// CommentFailed has the rejection or retry later text.
+	CommentFailed string `yaml:"comment_failed"`
+	// Rejected has the reason GitHub gave.
+	Rejected  string `yaml:"rejected"`
+	RateLimit string `yaml:"rate_limit"`
+	// RateLimitUntil has the UTC time the rate limit resets at.
+	RateLimitUntil    string `yaml:"rate_limit_until"`
+	GitHubUnavailable string `yaml:"github_unavailable"`
+
+	ContinueButton   string `yaml:"continue_button"`
+	CancelButton     string `yaml:"cancel_button"`
+	SubmitButton     string `yaml:"submit_button"`
+	EditButton       string `yaml:"edit_button"`
+	CommentButton    string `yaml:"comment_button"`
+	DoneButton       string `yaml:"done_button"`
+	PreviousButton   string `yaml:"previous_button"`
+	NextButton       string `yaml:"next_button"`
+	UrlField         string `yaml:"url_field"`
+	DescriptionField string `yaml:"description_field"`
+	LevelField       string `yaml:"level_field"`
+	TopicsField      string `yaml:"topics_field"`
+}
+
+var placeholderPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+func DefaultMessages() *Messages {
+	return &Messages{
+		LanguageName:    "English",
+		CancelKeyword:   "cancel",
+		Start:           "Hello! I can help you to quickly propose an article for DE or DIE: Digest. Start with %s command and follow the instructions.",
+		Help:            "Supported commands:\n%s - Propose an article for DE or DIE: Digest.\n%s - Return to the previous step of the article draft.\n%s <field> - Change a single field of the article draft: url, description, level or topics.\n%s - List your recent proposals and their state.\n%s - Choose the language of the bot.",
+		DraftExpired:    "Your article draft has expired due to inactivity. Start over with %s command.",
+		Cancelled:       "The operation was cancelled.",
+		NoDraftToEdit:   "There is no article draft to edit. Start with %s command.",
+		NoDraftToSubmit: "There is no article draft to submit. Start with %s command.",
+		FirstStep:       "You are already at the first step.",
+		ChooseLanguage:  "Choose the language of the bot:",
+		LanguageChanged: "Done, the bot speaks English now.",
+		UnknownLanguage: "Unknown language, please choose one of the buttons.",
+
+		MySubmissions:     "Your recent proposals:",
+		NoSubmissions:     "You haven't proposed any articles yet. Start with %s command.",
+		SubmissionItem:    "%s\nSubmitted: %s\nGitHub issue: %s\nState: %s",
+		IssueOpen:         "open",
+		IssueClosed:       "closed",
+		IssueInDigest:     "included in the digest %s",
+		IssueStateUnknown: "unknown, GitHub is unavailable",
+		ArticleClosed:     "The GitHub issue of your article \"%s\" was closed: %s",
+		ArticleLabeled:    "The GitHub issue of your article \"%s\" got the \"%s\" label: %s",
+		ArticleInDigest:   "Great news! Your article \"%s\" was included in the digest %s: %s",
+
+		UrlPrompt:                 "Step 1. Provide article URL. To abort the operation type \"%s\".",
+		InvalidUrl:                "Provided input is not a valid URL, please fix the URL or abort the operation by typing \"%s\".",
+		ExtractFailed:             "Failed to fetch the article, please check the URL and send it again or abort the operation by typing \"%s\".",
+		FetchFailed:               "Operation failed on fetching article.",
+		ArticleFound:              "Found the article:\nTitle: %s\nAuthor: %s\nLanguage: %s\n\nPress \"Continue\" if this is the right article or send a corrected URL. To abort the operation type \"%s\".",
+		TitleGuessed:              "The article page couldn't be read, so the title is made up from the URL. Please make sure the link isn't dead or paywalled.",
+		UnknownValue:              "unknown",
+		DescriptionPrompt:         "Step 2. Provide article description as a plain text. To abort the operation type \"%s\".",
+		LevelPrompt:               "Step 3. Choose level below. To abort the operation type \"%s\".",
+		UnknownLevel:              "Unknown level \"%s\", please choose one of the buttons below. To abort the operation type \"%s\".",
+		TopicsPrompt:              "Step 4. Choose topics below or type new ones as a comma separated list, then press \"Done\". To abort the operation type \"%s\".",
+		AllowedTopicsPrompt:       "Step 4. Choose topics below, then press \"Done\". To abort the operation type \"%s\".",
+		SelectedTopics:            "\nSelected: %s",
+		NoTopicsChosen:            "Choose at least one topic or type a new one.",
+		NewTopic:                  "\"%s\" is a new topic, did you mean: %s?",
+		TopicNotAllowed:           "\"%s\" is not one of the allowed topics, please choose them with the buttons.",
+		TopicNotAllowedSuggestion: "\"%s\" is not one of the allowed topics, did you mean: %s?",
+		EditTopicsHint:            "\nUse %s topics to change them.",
+		CurrentValue:              "\nCurrent value: %s",
+		Preview:                   "Step 5. Review the GitHub issue before submitting.\n\nTitle: %s\nLabels: %s\n\n%s",
+		UsePreviewButtons:         "Please use the buttons below the preview to submit the article, edit a field or cancel the operation.",
+		ChooseField:               "Which field do you want to edit?",
+		UnknownField:              "Unknown field, please choose one of the buttons.",
+
+		Duplicate:          "This article is already a digest candidate: %s\nDo you want to add your review there as a comment instead?",
+		IssueCreated:       "The article was added to the digest candidates! GitHub issue link: %s",
+		QueuedIssueCreated: "Your queued article was added to the digest candidates! GitHub issue link: %s",
+		Queued:             "GitHub is temporarily unavailable, so your article is queued. You will get the GitHub issue link here once it's submitted.",
+		CreateIssueFailed:  "Operation failed on creating GitHub issue.%s",
+		QueuedIssueFailed:  "Sorry, your queued article could not be added to the digest candidates.%s Please propose it again later or contact the digest authors.",
+		CommentAdded:       "Your review was added to the existing digest candidate! GitHub comment link: %s",
+		CommentFailed:      "Operation failed on adding a comment to GitHub issue.%s",
+		Rejected:           " GitHub rejected it: %s.",
+		RateLimit:          "GitHub rate limit is exceeded. Your draft is kept, please submit it again later.",
+		RateLimitUntil:     "GitHub rate limit is exceeded. Your draft is kept, please submit it again after %s UTC.",
+		GitHubUnavailable:  "GitHub is temporarily unavailable. Your draft is kept, please submit it again later.",
+
+		ContinueButton:   "Continue",
+		CancelButton:     "Cancel",
+		SubmitButton:     "Submit",
+		EditButton:       "Edit field",
+		CommentButton:    "Add comment",
+		DoneButton:       "Done",
+		PreviousButton:   "« Previous",
+		NextButton:       "Next »",
+		UrlField:         "URL",
+		DescriptionField: "Description",
+		LevelField:       "Level",
+		TopicsField:      "Topics",
+	}
+}
+
+func (m *Messages) Validate() error {
+	defaults := reflect.ValueOf(DefaultMessages()).Elem()
+	messages := reflect.ValueOf(m).Elem()
+	for i := 0; i < messages.NumField(); i++ {
+		name := messages.Type().Field(i).Tag.Get("yaml")
+		message := messages.Field(i).String()
+		if message == "" {
+			return fmt.Errorf("message %q is empty", name)
+		}
+
+		expected := placeholderPattern.FindAllString(defaults.Field(i).String(), -1)
+		if actual := placeholderPattern.FindAllString(message, -1); !slices.Equal(expected, actual) {
+			return fmt.Errorf("message %q has placeholders %v, expected %v", name, actual, expected)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package telegram
+
+func RussianMessages() *Messages {
+	return &Messages{
+		LanguageName:    "Русский",
+		CancelKeyword:   "отмена",
+		Start:           "Привет! Я помогу быстро предложить статью для DE or DIE: Digest. Начните с команды %s и следуйте инструкциям.",
+		Help:            "Поддерживаемые команды:\n%s - Предложить статью для DE or DIE: Digest.\n%s - Вернуться к предыдущему шагу черновика статьи.\n%s <field> - Изменить одно поле черновика статьи: url, description, level или topics.\n%s - Показать ваши последние предложения и их статус.\n%s - Выбрать язык бота.",
+		DraftExpired:    "Черновик статьи удалён из-за неактивности. Начните заново с команды %s.",
+		Cancelled:       "Операция отменена.",
+		NoDraftToEdit:   "Нет черновика статьи для редактирования. Начните с команды %s.",
+		NoDraftToSubmit: "Нет черновика статьи для отправки. Начните с команды %s.",
+		FirstStep:       "Вы уже на первом шаге.",
+		ChooseLanguage:  "Выберите язык бота:",
+		LanguageChanged: "Готово, теперь бот говорит по-русски.",
+		UnknownLanguage: "Неизвестный язык, пожалуйста, выберите одну из кнопок.",
+
+		MySubmissions:     "Ваши последние предложения:",
+		NoSubmissions:     "Вы ещё не предлагали статей. Начните с команды %s.",
+		SubmissionItem:    "%s\nОтправлено: %s\nGitHub issue: %s\nСтатус: %s",
+		IssueOpen:         "открыт",
+		IssueClosed:       "закрыт",
+		IssueInDigest:     "включён в дайджест %s",
+		IssueStateUnknown: "неизвестен, GitHub недоступен",
+		ArticleClosed:     "GitHub issue вашей статьи \"%s\" закрыт: %s",
+		ArticleLabeled:    "GitHub issue вашей статьи \"%s\" получил метку \"%s\": %s",
+		ArticleInDigest:   "Отличные новости! Ваша статья \"%s\" включена в дайджест %s: %s",
+
+		UrlPrompt:                 "Шаг 1. Пришлите ссылку на статью. Чтобы прервать операцию, напишите \"%s\".",
+		InvalidUrl:                "Это не похоже на ссылку, пожалуйста, исправьте её или прервите операцию, написав \"%s\".",
+		ExtractFailed:             "Не удалось загрузить статью, пожалуйста, проверьте ссылку и пришлите её снова или прервите операцию, написав \"%s\".",
+		FetchFailed:               "Не удалось загрузить статью.",
+		ArticleFound:              "Найдена статья:\nНазвание: %s\nАвтор: %s\nЯзык: %s\n\nНажмите \"Продолжить\", если это та статья, или пришлите исправленную ссылку. Чтобы прервать операцию, напишите \"%s\".",
+		TitleGuessed:              "Не удалось прочитать страницу статьи, поэтому название составлено по ссылке. Пожалуйста, проверьте, что ссылка рабочая и статья не за пейволом.",
+		UnknownValue:              "неизвестно",
+		DescriptionPrompt:         "Шаг 2. Пришлите описание статьи обычным текстом. Чтобы прервать операцию, напишите \"%s\".",
+		LevelPrompt:               "Шаг 3. Выберите уровень ниже. Чтобы прервать операцию, напишите \"%s\".",
+		UnknownLevel:              "Неизвестный уровень \"%s\", пожалуйста, выберите одну из кнопок ниже. Чтобы прервать операцию, напишите \"%s\".",
+		TopicsPrompt:              "Шаг 4. Выберите темы ниже или напишите новые через запятую, затем нажмите \"Готово\". Чтобы прервать операцию, напишите \"%s\".",
+		AllowedTopicsPrompt:       "Шаг 4. Выберите темы ниже, затем нажмите \"Готово\". Чтобы прервать операцию, напишите \"%s\".",
+		SelectedTopics:            "\nВыбрано: %s",
+		NoTopicsChosen:            "Выберите хотя бы одну тему или напишите новую.",
+		NewTopic:                  "\"%s\" - новая тема, возможно, вы имели в виду: %s?",
+		TopicNotAllowed:           "\"%s\" не входит в список разрешённых тем, пожалуйста, выберите темы кнопками.",
+		TopicNotAllowedSuggestion: "\"%s\" не входит в список разрешённых тем, возможно, вы имели в виду: %s?",
+		EditTopicsHint:            "\nЧтобы изменить темы, используйте %s topics.",
+		CurrentValue:              "\nТекущее значение: %s",
+		Preview:                   "Шаг 5. Проверьте GitHub issue перед отправкой.\n\nЗаголовок: %s\nМетки: %s\n\n%s",
+		UsePreviewButtons:         "Пожалуйста, используйте кнопки под предпросмотром, чтобы отправить статью, изменить поле или отменить операцию.",
+		ChooseField:               "Какое поле вы хотите изменить?",
+		UnknownField:              "Неизвестное поле, пожалуйста, выберите одну из кнопок.",
+
+		Duplicate:          "Эта статья уже предложена в дайджест: %s\nХотите добавить свой отзыв туда в виде комментария?",
+		IssueCreated:       "Статья добавлена в кандидаты дайджеста! Ссылка на GitHub issue: %s",
+		QueuedIssueCreated: "Ваша статья из очереди добавлена в кандидаты дайджеста! Ссылка на GitHub issue: %s",
+		Queued:             "GitHub временно недоступен, поэтому ваша статья поставлена в очередь. Ссылка на GitHub issue придёт сюда, когда статья будет отправлена.",
+		CreateIssueFailed:  "Не удалось создать GitHub issue.%s",
+		QueuedIssueFailed:  "К сожалению, вашу статью из очереди не удалось добавить в кандидаты дайджеста.%s Пожалуйста, предложите её позже или свяжитесь с авторами дайджеста.",
+		CommentAdded:       "Ваш отзыв добавлен к уже предложенной статье! Ссылка на комментарий в GitHub: %s",
+		CommentFailed:      "Не удалось добавить комментарий к GitHub issue.%s",
+		Rejected:           " GitHub отклонил запрос: %s.",
+		RateLimit:          "Превышен лимит запросов к GitHub. Черновик сохранён, пожалуйста, отправьте его позже.",
+		RateLimitUntil:     "Превышен лимит запросов к GitHub. Черновик сохранён, пожалуйста, отправьте его после %s UTC.",
+		GitHubUnavailable:  "GitHub временно недоступен. Черновик сохранён, пожалуйста, отправьте его позже.",
+
+		ContinueButton:   "Продолжить",
+		CancelButton:     "Отменить",
+		SubmitButton:     "Отправить",
+		EditButton:       "Изменить поле",
+		CommentButton:    "Добавить комментарий",
+		DoneButton:       "Готово",
+		PreviousButton:   "« Назад",
+		NextButton:       "Дальше »",
+		UrlField:         "Ссылка",
+		DescriptionField: "Описание",
+		LevelField:       "Уровень",
+		TopicsField:      "Темы",
+	}
+}
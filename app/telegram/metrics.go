@@ -0,0 +1,156 @@
+package telegram
+
+import (
+	"context"
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
+)
+
+const (
+	submissionCreated = "created"
+	submissionQueued  = "queued"
+	submissionFailed  = "failed"
+)
+
+type botMetrics struct {
+	registry          *prometheus.Registry
+	draftSteps        *prometheus.CounterVec
+	submissions       *prometheus.CounterVec
+	cancellations     *prometheus.CounterVec
+	extractorDuration prometheus.Histogram
+	extractorErrors   prometheus.Counter
+	githubDuration    *prometheus.HistogramVec
+	githubErrors      *prometheus.CounterVec
+}
+
+func newBotMetrics(stateStorage *StateStorage) *botMetrics {
+	m := &botMetrics{
+		registry: prometheus.NewRegistry(),
+		draftSteps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "deordie_bot_draft_steps_total",
+			Help: "Draft steps the users were prompted for, by step.",
+		}, []string{"step"}),
+		submissions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "deordie_bot_issue_submissions_total",
+			Help: "Attempts to create the GitHub issue of a draft, by result: created, queued or failed.",
+		}, []string{"result"}),
+		cancellations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "deordie_bot_cancellations_total",
+			Help: "Drafts cancelled by the users, by the step they were cancelled at.",
+		}, []string{"step"}),
+		extractorDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name: "deordie_bot_extractor_request_duration_seconds",
+			Help: "Duration of the article extraction.",
+		}),
+		extractorErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "deordie_bot_extractor_errors_total",
+			Help: "Failed article extractions.",
+		}),
+		githubDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "deordie_bot_github_request_duration_seconds",
+			Help: "Duration of the GitHub API calls, by operation.",
+		}, []string{"operation"}),
+		githubErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "deordie_bot_github_errors_total",
+			Help: "Failed GitHub API calls, by operation.",
+		}, []string{"operation"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "deordie_bot_active_drafts",
+			Help: "Article drafts not submitted, cancelled or expired yet.",
+		}, func() float64 {
+			return float64(len(stateStorage.Items()))
+		}),
+		m.draftSteps,
+		m.submissions,
+		m.cancellations,
+		m.extractorDuration,
+		m.extractorErrors,
+		m.githubDuration,
+		m.githubErrors,
+	)
+	return m
+}
+
+func (m *botMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+type instrumentedExtractor struct {
+	next    articleExtractor
+	metrics *botMetrics
+}
+
+func (e *instrumentedExtractor) ExtractArticle(ctx context.Context, articleUrl string) (*extractor.Article, error) {
+	start := time.Now()
+	article, err := e.next.ExtractArticle(ctx, articleUrl)
+	e.metrics.extractorDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		e.metrics.extractorErrors.Inc()
+	}
+	return article, err
+}
+
+type githubAPI interface {
+	githubIssueCreator
+	githubIssueFinder
+	githubLabelLister
+	githubIssueGetter
+	githubIssueCommenter
+}
+
+type instrumentedGitHub struct {
+	next    githubAPI
+	metrics *botMetrics
+}
+
+func (g *instrumentedGitHub) observe(operation string, start time.Time, err error) {
+	g.metrics.githubDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		g.metrics.githubErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func (g *instrumentedGitHub) CreateIssue(ctx context.Context, article *github.ArticleIssue) (string, error) {
+	start := time.Now()
+	issueUrl, err := g.next.CreateIssue(ctx, article)
+	g.observe("CreateIssue", start, err)
+	return issueUrl, err
+}
+
+func (g *instrumentedGitHub) FindIssueByUrl(ctx context.Context, articleUrl string) (*github.Issue, error) {
+	start := time.Now()
+	issue, err := g.next.FindIssueByUrl(ctx, articleUrl)
+	g.observe("FindIssueByUrl", start, err)
+	return issue, err
+}
+
+func (g *instrumentedGitHub) ListLabels(ctx context.Context) ([]github.Label, error) {
+	start := time.Now()
+	labels, err := g.next.ListLabels(ctx)
+	g.observe("ListLabels", start, err)
+	return labels, err
+}
+
+func (g *instrumentedGitHub) GetIssue(ctx context.Context, issueNumber int) (*github.Issue, error) {
+	start := time.Now()
+	issue, err := g.next.GetIssue(ctx, issueNumber)
+	g.observe("GetIssue", start, err)
+	return issue, err
+}
+
+func (g *instrumentedGitHub) AddComment(ctx context.Context, issueNumber int, article *github.ArticleIssue) (string, error) {
+	start := time.Now()
+	commentUrl, err := g.next.AddComment(ctx, issueNumber, article)
+	g.observe("AddComment", start, err)
+	return commentUrl, err
+}
@@ -0,0 +1,81 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	tele "gopkg.in/telebot.v3"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func writeMetrics(t *testing.T, bot *Bot) string {
+	recorder := httptest.NewRecorder()
+	bot.metrics.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
+
+func TestInstrumentedGitHub_CountsErrors(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	githubClient := new(MockGitHubClient)
+	githubClient.On("CreateIssue", mock.Anything).Return("", fmt.Errorf("unavailable"))
+	githubClient.On("GetIssue", 12).Return(&github.Issue{Number: 12}, nil)
+	instrumented := &instrumentedGitHub{next: githubClient, metrics: bot.metrics}
+
+	_, _ = instrumented.CreateIssue(context.Background(), &github.ArticleIssue{})
+	_, _ = instrumented.GetIssue(context.Background(), 12)
+
+	out := writeMetrics(t, bot)
+	assert.Contains(t, out, `deordie_bot_github_errors_total{operation="CreateIssue"} 1`)
+	assert.NotContains(t, out, `deordie_bot_github_errors_total{operation="GetIssue"}`)
+	assert.Contains(t, out, `deordie_bot_github_request_duration_seconds_count{operation="GetIssue"} 1`)
+}
+
+func TestInstrumentedExtractor_CountsErrors(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	rapidApiClient := new(MockRapidAPIClient)
+	rapidApiClient.On("ExtractArticle", mock.Anything).Return((*extractor.Article)(nil), fmt.Errorf("quota exceeded"))
+	instrumented := &instrumentedExtractor{next: rapidApiClient, metrics: bot.metrics}
+
+	_, err := instrumented.ExtractArticle(context.Background(), "https://example.com")
+
+	assert.EqualError(t, err, "quota exceeded")
+	out := writeMetrics(t, bot)
+	assert.Contains(t, out, "deordie_bot_extractor_errors_total 1")
+	assert.Contains(t, out, "deordie_bot_extractor_request_duration_seconds_count 1")
+}
+
+func TestCancelHandler_CountsCancellationAndActiveDrafts(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	bot.stateStorage.Set(7000, UserArticleState{UserId: 7000, Step: stepDescription, Url: "https://example.com"})
+	bot.stateStorage.Set(7001, UserArticleState{UserId: 7001, Step: stepUrl})
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: 7000})
+
+	_ = bot.handleCancel(mockContext)
+
+	out := writeMetrics(t, bot)
+	assert.Contains(t, out, `deordie_bot_cancellations_total{step="description"} 1`)
+	assert.Contains(t, out, "deordie_bot_active_drafts 1")
+}
+
+func TestSubmit_CountsResult(t *testing.T) {
+	githubClient := new(MockGitHubClient)
+	githubClient.On("CreateIssue", mock.Anything).Return("https://github.com/owner/repo/issues/1", nil).Once()
+	githubClient.On("CreateIssue", mock.Anything).Return("", fmt.Errorf("invalid label"))
+	bot := newTestBot(nil, githubClient)
+	submission := Submission{UserId: 7002, Issue: &github.ArticleIssue{Url: "https://example.com"}}
+
+	_, _, _ = bot.submit(bot.outbox.Add(submission), submission, false)
+	_, _, _ = bot.submit(bot.outbox.Add(submission), submission, false)
+
+	out := writeMetrics(t, bot)
+	assert.Contains(t, out, `deordie_bot_issue_submissions_total{result="created"} 1`)
+	assert.Contains(t, out, `deordie_bot_issue_submissions_total{result="failed"} 1`)
+}
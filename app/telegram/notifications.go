@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/github"
+	tele "gopkg.in/telebot.v3"
+	"log/slog"
+	"strings"
+)
+
+const githubWebhookPath = "/github/webhook"
+
+type IssueOwner struct {
+	UserId   int64  `json:"user_id"`
+	Language string `json:"language,omitempty"`
+}
+
+func (b *Bot) handleIssueEvent(ctx context.Context, event *github.IssuesEvent) {
+	owner, ok := b.issueOwners.Get(int64(event.Issue.Number))
+	if !ok {
+		return
+	}
+
+	m := b.catalogs[b.language(owner.UserId, owner.Language)]
+	iss := &event.Issue
+	var text string
+	switch event.Action {
+	case github.IssueActionClosed:
+		text = fmt.Sprintf(m.ArticleClosed, iss.Title, iss.HtmlUrl)
+	case github.IssueActionLabeled:
+		// The level and topic labels come from the draft itself, so they're no news for the submitter.
+		if event.Label == nil || strings.HasPrefix(event.Label.Name, github.LevelLabelPrefix) || strings.HasPrefix(event.Label.Name, github.TopicLabelPrefix) {
+			return
+		}
+		text = fmt.Sprintf(m.ArticleLabeled, iss.Title, event.Label.Name, iss.HtmlUrl)
+	case github.IssueActionMilestoned:
+		milestone := event.Milestone
+		if milestone == nil {
+			milestone = iss.Milestone
+		}
+		if milestone == nil {
+			return
+		}
+		text = fmt.Sprintf(m.ArticleInDigest, iss.Title, milestone.Title, iss.HtmlUrl)
+	default:
+		return
+	}
+
+	if _, err := b.sender.Send(tele.ChatID(owner.UserId), text, tele.NoPreview); err != nil {
+		slog.ErrorContext(ctx, "Failed to notify user about GitHub issue", "user_id", owner.UserId, "issue_number", iss.Number, "error", err)
+	}
+}
@@ -0,0 +1,90 @@
+package telegram
+
+import (
+	"context"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	tele "gopkg.in/telebot.v3"
+	"testing"
+)
+
+func newIssuesEvent(action string, issueNumber int) *github.IssuesEvent {
+	return &github.IssuesEvent{
+		Action: action,
+		Issue: github.Issue{
+			Number:  issueNumber,
+			Title:   "Article Title / Noname Blog",
+			HtmlUrl: "https://github.com/owner/repo/issues/1",
+		},
+	}
+}
+
+func TestHandleIssueEvent_WhenClosed(t *testing.T) {
+	userId := int64(6000)
+	bot := newTestBot(nil, nil)
+	bot.issueOwners.Set(1, IssueOwner{UserId: userId})
+
+	bot.handleIssueEvent(context.Background(), newIssuesEvent(github.IssueActionClosed, 1))
+
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "The GitHub issue of your article \"Article Title / Noname Blog\" was closed: https://github.com/owner/repo/issues/1", mock.Anything)
+}
+
+func TestHandleIssueEvent_WhenLabeled(t *testing.T) {
+	userId := int64(6001)
+	bot := newTestBot(nil, nil)
+	bot.issueOwners.Set(2, IssueOwner{UserId: userId})
+	event := newIssuesEvent(github.IssueActionLabeled, 2)
+	event.Label = &github.Label{Name: "shortlist"}
+
+	bot.handleIssueEvent(context.Background(), event)
+
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "The GitHub issue of your article \"Article Title / Noname Blog\" got the \"shortlist\" label: https://github.com/owner/repo/issues/1", mock.Anything)
+}
+
+func TestHandleIssueEvent_WhenLabeledWithDraftLabel(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	bot.issueOwners.Set(3, IssueOwner{UserId: 6002})
+	event := newIssuesEvent(github.IssueActionLabeled, 3)
+	event.Label = &github.Label{Name: "topic:kafka"}
+
+	bot.handleIssueEvent(context.Background(), event)
+
+	bot.sender.(*MockSender).AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleIssueEvent_WhenMilestonedInSubmitterLanguage(t *testing.T) {
+	userId := int64(6003)
+	bot := newTestBot(nil, nil)
+	bot.issueOwners.Set(4, IssueOwner{UserId: userId, Language: "ru"})
+	event := newIssuesEvent(github.IssueActionMilestoned, 4)
+	event.Milestone = &github.Milestone{Number: 1, Title: "#42"}
+
+	bot.handleIssueEvent(context.Background(), event)
+
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "Отличные новости! Ваша статья \"Article Title / Noname Blog\" включена в дайджест #42: https://github.com/owner/repo/issues/1", mock.Anything)
+}
+
+func TestHandleIssueEvent_WhenIssueNotCreatedByBot(t *testing.T) {
+	bot := newTestBot(nil, nil)
+
+	bot.handleIssueEvent(context.Background(), newIssuesEvent(github.IssueActionClosed, 5))
+
+	bot.sender.(*MockSender).AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDrainOutbox_RecordsIssueOwner(t *testing.T) {
+	userId := int64(6004)
+	articleIssue := &github.ArticleIssue{Url: "https://example.com", Title: "Article Title"}
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", articleIssue.Url).Return((*github.Issue)(nil), nil)
+	mockGitHub.On("CreateIssue", articleIssue).Return("https://github.com/owner/repo/issues/7", nil)
+	bot := newTestBot(nil, mockGitHub)
+	bot.outbox.Add(Submission{UserId: userId, Issue: articleIssue, Language: "ru", Attempts: 1})
+
+	bot.drainOutbox(context.Background())
+
+	owner, ok := bot.issueOwners.Get(7)
+	assert.True(t, ok)
+	assert.Equal(t, IssueOwner{UserId: userId, Language: "ru"}, owner)
+}
@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/logging"
+	"github.com/deordie/deordie-bot/app/retry"
+	"github.com/deordie/deordie-bot/app/storage"
+	tele "gopkg.in/telebot.v3"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	outboxPollInterval = 30 * time.Second
+
+	// maxSubmissionAttempts keeps a submission in the outbox for about half a day.
+	maxSubmissionAttempts   = 16
+	submissionRetryBaseWait = time.Minute
+	submissionRetryMaxWait  = time.Hour
+)
+
+type Submission struct {
+	UserId        int64                `json:"user_id"`
+	Issue         *github.ArticleIssue `json:"issue"`
+	Language      string               `json:"language,omitempty"`
+	Attempts      int                  `json:"attempts"`
+	NextAttemptAt time.Time            `json:"next_attempt_at"`
+	CorrelationId string               `json:"correlation_id,omitempty"`
+}
+
+type Outbox struct {
+	storage.Storage[Submission]
+	mutex  sync.Mutex
+	lastId int64
+}
+
+func NewOutbox(s storage.Storage[Submission]) *Outbox {
+	return &Outbox{
+		Storage: s,
+	}
+}
+
+func (o *Outbox) Add(submission Submission) int64 {
+	o.mutex.Lock()
+	id := time.Now().UnixNano()
+	if id <= o.lastId {
+		id = o.lastId + 1
+	}
+	o.lastId = id
+	o.mutex.Unlock()
+
+	o.Set(id, submission)
+	return id
+}
+
+func (o *Outbox) Due(now time.Time) map[int64]Submission {
+	due := make(map[int64]Submission)
+	for id, submission := range o.Items() {
+		if !now.Before(submission.NextAttemptAt) {
+			due[id] = submission
+		}
+	}
+	return due
+}
+
+func (b *Bot) runOutboxWorker(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		b.drainOutbox(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Bot) drainOutbox(ctx context.Context) {
+	for id, submission := range b.outbox.Due(time.Now()) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// The bot may have stopped after GitHub created the issue of an earlier attempt.
+		issueUrl, queued, err := b.submit(id, submission, true)
+		m := b.catalogs[b.language(submission.UserId, submission.Language)]
+		switch {
+		case err == nil:
+			b.notifySubmitter(submission.UserId, fmt.Sprintf(m.QueuedIssueCreated, issueUrl))
+		case queued:
+			slog.WarnContext(submissionContext(submission), "Failed to create GitHub issue for queued submission, will retry", "submission_id", id, "attempts", submission.Attempts+1, "error", err)
+		default:
+			slog.ErrorContext(submissionContext(submission), "Gave up creating GitHub issue for queued submission", "submission_id", id, "error", err)
+			b.notifySubmitter(submission.UserId, fmt.Sprintf(m.QueuedIssueFailed, getRejectionText(m, err)))
+		}
+	}
+}
+
+func (b *Bot) submit(id int64, submission Submission, lookUp bool) (issueUrl string, queued bool, err error) {
+	callCtx, cancel := b.newCallContext(submissionContext(submission))
+	defer cancel()
+
+	issueUrl, err = b.createIssue(callCtx, submission, lookUp)
+	if err == nil {
+		b.outbox.Delete(id)
+		b.history.Add(SubmissionRecord{
+			UserId:      submission.UserId,
+			Url:         submission.Issue.Url,
+			Title:       submission.Issue.Title,
+			IssueUrl:    issueUrl,
+			SubmittedAt: time.Now(),
+		})
+		if issueNumber, ok := github.IssueNumber(issueUrl); ok {
+			b.issueOwners.Set(int64(issueNumber), IssueOwner{UserId: submission.UserId, Language: submission.Language})
+		}
+		b.metrics.submissions.WithLabelValues(submissionCreated).Inc()
+		return issueUrl, false, nil
+	}
+
+	submission.Attempts++
+	if !isTemporaryError(err) || submission.Attempts >= maxSubmissionAttempts {
+		b.outbox.Delete(id)
+		b.metrics.submissions.WithLabelValues(submissionFailed).Inc()
+		return "", false, err
+	}
+
+	submission.NextAttemptAt = nextSubmissionAttemptAt(err, submission.Attempts, time.Now())
+	b.outbox.Set(id, submission)
+	b.metrics.submissions.WithLabelValues(submissionQueued).Inc()
+	return "", true, err
+}
+
+func (b *Bot) createIssue(ctx context.Context, submission Submission, lookUp bool) (string, error) {
+	if lookUp {
+		existingIssue, err := b.githubIssueFinder.FindIssueByUrl(ctx, submission.Issue.Url)
+		if err != nil {
+			return "", err
+		}
+		if existingIssue != nil {
+			return existingIssue.HtmlUrl, nil
+		}
+	}
+	return b.githubIssueCreator.CreateIssue(ctx, submission.Issue)
+}
+
+func (b *Bot) notifySubmitter(userId int64, text string) {
+	if _, err := b.sender.Send(tele.ChatID(userId), text, tele.RemoveKeyboard); err != nil {
+		slog.Error("Failed to notify user about queued submission", "user_id", userId, "error", err)
+	}
+}
+
+func submissionContext(submission Submission) context.Context {
+	return logging.WithCorrelationId(context.Background(), submission.CorrelationId)
+}
+
+func isTemporaryError(err error) bool {
+	var rateLimitErr *github.RateLimitError
+	var temporaryErr *retry.TemporaryError
+	return errors.As(err, &rateLimitErr) || errors.As(err, &temporaryErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func nextSubmissionAttemptAt(err error, attempts int, now time.Time) time.Time {
+	wait := submissionRetryBaseWait << (attempts - 1)
+	if wait <= 0 || wait > submissionRetryMaxWait {
+		wait = submissionRetryMaxWait
+	}
+	next := now.Add(wait)
+
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.ResetAt.After(next) {
+		next = rateLimitErr.ResetAt
+	}
+	return next
+}
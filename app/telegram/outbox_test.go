@@ -0,0 +1,135 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	tele "gopkg.in/telebot.v3"
+	"testing"
+	"time"
+)
+
+func TestDrainOutbox_WhenIssueCreated(t *testing.T) {
+	userId := int64(2000)
+	articleIssue := &github.ArticleIssue{Url: "https://example.com", Title: "Article Title"}
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", articleIssue.Url).Return((*github.Issue)(nil), nil)
+	mockGitHub.On("CreateIssue", articleIssue).Return("https://github.com/owner/repo/issues/1", nil)
+	bot := newTestBot(nil, mockGitHub)
+	bot.outbox.Add(Submission{UserId: userId, Issue: articleIssue, Attempts: 1})
+
+	bot.drainOutbox(context.Background())
+
+	assert.Empty(t, bot.outbox.Items(), "expected the submission to leave the outbox")
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "Your queued article was added to the digest candidates! GitHub issue link: https://github.com/owner/repo/issues/1", mock.Anything)
+}
+
+func TestDrainOutbox_WhenStillUnavailable(t *testing.T) {
+	userId := int64(2001)
+	articleIssue := &github.ArticleIssue{Url: "https://example.com", Title: "Article Title"}
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", articleIssue.Url).Return((*github.Issue)(nil), nil)
+	mockGitHub.On("CreateIssue", articleIssue).Return("", &retry.TemporaryError{Attempts: 3, Err: fmt.Errorf("non-successful HTTP status code in CreateIssue call: 502")})
+	bot := newTestBot(nil, mockGitHub)
+	id := bot.outbox.Add(Submission{UserId: userId, Issue: articleIssue, Attempts: 2})
+
+	bot.drainOutbox(context.Background())
+
+	submission, ok := bot.outbox.Get(id)
+	assert.True(t, ok, "expected the submission to stay queued")
+	assert.Equal(t, 3, submission.Attempts)
+	assert.True(t, submission.NextAttemptAt.After(time.Now().Add(3*time.Minute)), "expected the wait to double with every attempt")
+	bot.sender.(*MockSender).AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDrainOutbox_WhenPermanentlyFailed(t *testing.T) {
+	userId := int64(2002)
+	articleIssue := &github.ArticleIssue{Url: "https://example.com", Title: "Article Title"}
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", articleIssue.Url).Return((*github.Issue)(nil), nil)
+	mockGitHub.On("CreateIssue", articleIssue).Return("", fmt.Errorf("non-successful HTTP status code in CreateIssue call: 422"))
+	bot := newTestBot(nil, mockGitHub)
+	bot.outbox.Add(Submission{UserId: userId, Issue: articleIssue, Attempts: 1})
+
+	bot.drainOutbox(context.Background())
+
+	assert.Empty(t, bot.outbox.Items(), "expected the submission to be dropped")
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "Sorry, your queued article could not be added to the digest candidates. Please propose it again later or contact the digest authors.", mock.Anything)
+}
+
+func TestDrainOutbox_SkipsSubmissionsNotDue(t *testing.T) {
+	mockGitHub := new(MockGitHubClient)
+	bot := newTestBot(nil, mockGitHub)
+	bot.outbox.Add(Submission{UserId: 2003, Issue: &github.ArticleIssue{Url: "https://example.com"}, Attempts: 1, NextAttemptAt: time.Now().Add(time.Hour)})
+
+	bot.drainOutbox(context.Background())
+
+	mockGitHub.AssertNotCalled(t, "CreateIssue", mock.Anything)
+	assert.Len(t, bot.outbox.Items(), 1)
+}
+
+func TestDrainOutbox_WhenPreviousAttemptCreatedIssue(t *testing.T) {
+	userId := int64(2004)
+	articleIssue := &github.ArticleIssue{Url: "https://example.com/article", Title: "Sample Title"}
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", articleIssue.Url).Return(&github.Issue{Number: 3, HtmlUrl: "https://github.com/owner/repo/issues/3"}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	bot.outbox.Add(Submission{UserId: userId, Issue: articleIssue, Attempts: 1})
+
+	bot.drainOutbox(context.Background())
+
+	mockGitHub.AssertNotCalled(t, "CreateIssue", mock.Anything)
+	assert.Empty(t, bot.outbox.Items())
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "Your queued article was added to the digest candidates! GitHub issue link: https://github.com/owner/repo/issues/3", mock.Anything)
+}
+
+func TestDrainOutbox_WhenIssueCreatedBeforeRestart(t *testing.T) {
+	userId := int64(2006)
+	articleIssue := &github.ArticleIssue{Url: "https://example.com/article", Title: "Sample Title"}
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", articleIssue.Url).Return(&github.Issue{Number: 4, HtmlUrl: "https://github.com/owner/repo/issues/4"}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	bot.outbox.Add(Submission{UserId: userId, Issue: articleIssue, Attempts: 0})
+
+	bot.drainOutbox(context.Background())
+
+	mockGitHub.AssertNotCalled(t, "CreateIssue", mock.Anything)
+	assert.Empty(t, bot.outbox.Items())
+	bot.sender.(*MockSender).AssertCalled(t, "Send", tele.ChatID(userId), "Your queued article was added to the digest candidates! GitHub issue link: https://github.com/owner/repo/issues/4", mock.Anything)
+}
+
+func TestDrainOutbox_SkipsSubmissionBeingSubmitted(t *testing.T) {
+	userId := int64(2005)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("FindIssueByUrl", "https://example.com/article").Return((*github.Issue)(nil), nil)
+	release := make(chan struct{})
+	mockGitHub.On("CreateIssue", mock.Anything).Run(func(mock.Arguments) { <-release }).Return("https://github.com/owner/repo/issues/1", nil).Once()
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	bot.stateStorage.Set(userId, UserArticleState{
+		UserId:      userId,
+		Url:         "https://example.com/article",
+		Description: "Nice article.",
+		Level:       "advanced",
+		Topics:      []string{"kafka"},
+		Article:     &extractor.Article{Title: "Article Title", Url: "https://example.com/article"},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		_ = bot.handleSubmit(mockContext)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return len(bot.outbox.Items()) == 1 }, time.Second, time.Millisecond)
+	bot.drainOutbox(context.Background())
+	close(release)
+	<-done
+
+	mockGitHub.AssertNumberOfCalls(t, "CreateIssue", 1)
+}
@@ -0,0 +1,147 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/deordie/deordie-bot/app/health"
+	tele "gopkg.in/telebot.v3"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+	maxUpdateSize = 1 << 20
+
+	serverReadHeaderTimeout = 10 * time.Second
+	serverReadTimeout       = 30 * time.Second
+	serverWriteTimeout      = 30 * time.Second
+	serverIdleTimeout       = 2 * time.Minute
+
+	healthPath    = "/healthz"
+	readinessPath = "/readyz"
+	metricsPath   = "/metrics"
+
+	readinessTtl     = time.Minute
+	readinessTimeout = 5 * time.Second
+)
+
+type ServerSettings struct {
+	Polling              bool
+	RemoveWebhook        bool
+	PublicUrl            string
+	ListenAddress        string
+	MetricsListenAddress string
+	SecretToken          string
+	TLSCertPath          string
+	TLSKeyPath           string
+	GitHubWebhookSecret  string
+}
+
+func (b *Bot) newServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthPath, health.Liveness)
+	mux.Handle(readinessPath, b.readiness)
+	if b.githubWebhook != nil {
+		mux.Handle(githubWebhookPath, b.githubWebhook)
+	}
+	if !b.server.Polling {
+		mux.Handle("/", newUpdateHandler(b.dispatch, b.server.SecretToken))
+	}
+
+	return newHttpServer(b.server.ListenAddress, mux)
+}
+
+func (b *Bot) newMetricsServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthPath, health.Liveness)
+	mux.Handle(metricsPath, b.metrics.handler())
+	return newHttpServer(b.server.MetricsListenAddress, mux)
+}
+
+func newHttpServer(address string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              address,
+		Handler:           handler,
+		ReadHeaderTimeout: serverReadHeaderTimeout,
+		ReadTimeout:       serverReadTimeout,
+		WriteTimeout:      serverWriteTimeout,
+		IdleTimeout:       serverIdleTimeout,
+	}
+}
+
+func newReadiness(telebot *tele.Bot, token string, githubClient *github.Client, articleExtractor articleExtractor) *health.Readiness {
+	probes := []health.Probe{
+		{Name: "telegram", Check: func(_ context.Context) error {
+			if _, err := telebot.Raw("getMe", nil); err != nil {
+				return errors.New(strings.ReplaceAll(err.Error(), token, "<token>"))
+			}
+			return nil
+		}},
+		{Name: "github", Check: githubClient.CheckAccess},
+	}
+	if checker, ok := articleExtractor.(extractor.Checker); ok {
+		probes = append(probes, health.Probe{Name: "extractor", Check: checker.Check})
+	}
+	return health.NewReadiness(readinessTtl, readinessTimeout, probes...)
+}
+
+func (b *Bot) serve(server *http.Server, tls bool) {
+	var err error
+	if tls {
+		err = server.ListenAndServeTLS(b.server.TLSCertPath, b.server.TLSKeyPath)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		b.serverErrors <- fmt.Errorf("error occurred during serving HTTP on %s: %w", server.Addr, err)
+	}
+}
+
+func (b *Bot) Errors() <-chan error {
+	return b.serverErrors
+}
+
+func newUpdateHandler(dispatch func(update tele.Update), secretToken string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		token := r.Header.Get(secretTokenHeader)
+		if secretToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secretToken)) != 1 {
+			slog.Warn("Rejected Telegram update with an invalid secret token", "remote_addr", r.RemoteAddr)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.ContentLength > maxUpdateSize {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		var update tele.Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUpdateSize)).Decode(&update); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				w.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			slog.Warn("Cannot decode Telegram update", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		dispatch(update)
+	})
+}
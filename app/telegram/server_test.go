@@ -0,0 +1,237 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"github.com/deordie/deordie-bot/app/health"
+	"github.com/stretchr/testify/assert"
+	tele "gopkg.in/telebot.v3"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const update = `{"update_id": 1, "message": {"message_id": 1, "text": "/start"}}`
+
+func newUpdateRequest(body string, secretToken string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if secretToken != "" {
+		req.Header.Set(secretTokenHeader, secretToken)
+	}
+	return req
+}
+
+// recordingDispatcher keeps the updates dispatched by the update handler.
+type recordingDispatcher struct {
+	updates []tele.Update
+}
+
+func (d *recordingDispatcher) dispatch(update tele.Update) {
+	d.updates = append(d.updates, update)
+}
+
+func TestUpdateHandler(t *testing.T) {
+	dispatcher := &recordingDispatcher{}
+	recorder := httptest.NewRecorder()
+
+	newUpdateHandler(dispatcher.dispatch, "secret").ServeHTTP(recorder, newUpdateRequest(update, "secret"))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, dispatcher.updates, 1)
+	assert.Equal(t, 1, dispatcher.updates[0].ID)
+	assert.Equal(t, "/start", dispatcher.updates[0].Message.Text)
+}
+
+func TestUpdateHandler_WhenNoSecretTokenConfigured(t *testing.T) {
+	dispatcher := &recordingDispatcher{}
+	recorder := httptest.NewRecorder()
+
+	newUpdateHandler(dispatcher.dispatch, "").ServeHTTP(recorder, newUpdateRequest(update, ""))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, dispatcher.updates, 1)
+}
+
+func TestUpdateHandler_WhenInvalidSecretToken(t *testing.T) {
+	for _, token := range []string{"", "wrong", "secret2"} {
+		dispatcher := &recordingDispatcher{}
+		recorder := httptest.NewRecorder()
+
+		newUpdateHandler(dispatcher.dispatch, "secret").ServeHTTP(recorder, newUpdateRequest(update, token))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, "token %q", token)
+		assert.Empty(t, dispatcher.updates, "token %q", token)
+	}
+}
+
+func TestUpdateHandler_WhenTooLarge(t *testing.T) {
+	dispatcher := &recordingDispatcher{}
+	recorder := httptest.NewRecorder()
+
+	newUpdateHandler(dispatcher.dispatch, "secret").ServeHTTP(recorder, newUpdateRequest(strings.Repeat(" ", maxUpdateSize+1), "secret"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Empty(t, dispatcher.updates)
+}
+
+func TestUpdateHandler_WhenTooLargeWithoutContentLength(t *testing.T) {
+	dispatcher := &recordingDispatcher{}
+	recorder := httptest.NewRecorder()
+	req := newUpdateRequest(strings.Repeat(" ", maxUpdateSize+1), "secret")
+	req.ContentLength = -1
+
+	newUpdateHandler(dispatcher.dispatch, "secret").ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Empty(t, dispatcher.updates)
+}
+
+func TestUpdateHandler_WhenMalformed(t *testing.T) {
+	dispatcher := &recordingDispatcher{}
+	recorder := httptest.NewRecorder()
+
+	newUpdateHandler(dispatcher.dispatch, "secret").ServeHTTP(recorder, newUpdateRequest(`{"update_id": `, "secret"))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Empty(t, dispatcher.updates)
+}
+
+func TestUpdateHandler_WhenNotPost(t *testing.T) {
+	dispatcher := &recordingDispatcher{}
+	recorder := httptest.NewRecorder()
+
+	newUpdateHandler(dispatcher.dispatch, "").ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestDispatch_HandlesUpdateBeforeStop(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	telebot, err := tele.NewBot(tele.Settings{Synchronous: true, Offline: true})
+	assert.NoError(t, err)
+	bot.telebot = telebot
+	handled := make(chan string, 1)
+	bot.telebot.Handle(startCommand, func(ctx tele.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		handled <- ctx.Text()
+		return nil
+	})
+	recorder := httptest.NewRecorder()
+
+	bot.newServer().Handler.ServeHTTP(recorder, newUpdateRequest(update, ""))
+	err = bot.Stop(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	select {
+	case text := <-handled:
+		assert.Equal(t, "/start", text)
+	default:
+		t.Fatal("Stop returned before the update was handled")
+	}
+}
+
+func TestServer_HealthReadinessAndMetrics(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	bot.server.Polling = true
+	bot.readiness = health.NewReadiness(time.Minute, time.Second, health.Probe{Name: "github", Check: func(_ context.Context) error {
+		return errors.New("bad credentials")
+	}})
+	server := bot.newServer()
+	metricsServer := bot.newMetricsServer()
+
+	testCases := []struct {
+		name       string
+		server     *http.Server
+		path       string
+		statusCode int
+		body       string
+	}{
+		{"liveness", server, healthPath, http.StatusOK, "ok"},
+		{"readiness", server, readinessPath, http.StatusServiceUnavailable, `"github":"bad credentials"`},
+		{"no metrics with the webhooks", server, metricsPath, http.StatusNotFound, ""},
+		{"no webhook in polling mode", server, "/", http.StatusNotFound, ""},
+		{"metrics server liveness", metricsServer, healthPath, http.StatusOK, "ok"},
+		{"metrics", metricsServer, metricsPath, http.StatusOK, "deordie_bot_active_drafts 0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			tc.server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.body)
+		})
+	}
+}
+
+func TestServe_ReportsListenError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err, "unexpected error")
+	defer listener.Close()
+	bot := newTestBot(nil, nil)
+	bot.server.Polling = true
+	bot.server.ListenAddress = listener.Addr().String()
+	bot.httpServer = bot.newServer()
+	bot.serverErrors = make(chan error, 1)
+
+	go bot.serve(bot.httpServer, false)
+
+	select {
+	case err := <-bot.Errors():
+		assert.ErrorContains(t, err, listener.Addr().String())
+	case <-time.After(time.Second):
+		t.Fatal("expected the listen error")
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	testCases := []struct {
+		name          string
+		webhookUrl    string
+		removeWebhook bool
+		err           string
+		removed       bool
+	}{
+		{"no webhook", "", false, "", false},
+		{"webhook not allowed to remove", "https://example.com/bot", false, "the Webhook https://example.com/bot is set for the bot, set TELEGRAM_REMOVE_WEBHOOK=true to remove it and poll the updates", false},
+		{"webhook allowed to remove", "https://example.com/bot", true, "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			removed := false
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/getWebhookInfo"):
+					_, _ = w.Write([]byte(`{"ok": true, "result": {"url": "` + tc.webhookUrl + `"}}`))
+				case strings.HasSuffix(r.URL.Path, "/deleteWebhook"):
+					removed = true
+					_, _ = w.Write([]byte(`{"ok": true, "result": true}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer mockServer.Close()
+			telebot, err := tele.NewBot(tele.Settings{URL: mockServer.URL, Token: "123:token", Offline: true})
+			assert.Nil(t, err, "unexpected error")
+			bot := newTestBot(nil, nil)
+			bot.telebot = telebot
+			bot.server.RemoveWebhook = tc.removeWebhook
+
+			err = bot.removeWebhook()
+
+			if tc.err == "" {
+				assert.Nil(t, err, "unexpected error")
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+			assert.Equal(t, tc.removed, removed)
+		})
+	}
+}
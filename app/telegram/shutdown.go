@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type inFlight struct {
+	mutex   sync.Mutex
+	running int
+	idle    chan struct{}
+}
+
+func (f *inFlight) run(fn func()) {
+	f.mutex.Lock()
+	f.running++
+	f.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+			f.running--
+			if f.running == 0 && f.idle != nil {
+				close(f.idle)
+				f.idle = nil
+			}
+		}()
+
+		fn()
+	}()
+}
+
+func (f *inFlight) wait(ctx context.Context) error {
+	f.mutex.Lock()
+	if f.running == 0 {
+		f.mutex.Unlock()
+		return nil
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	idle := f.idle
+	running := f.running
+	f.mutex.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d update handlers are still running: %w", running, ctx.Err())
+	}
+}
+
+func (b *Bot) Stop(ctx context.Context) error {
+	if b.httpServer != nil {
+		if err := b.httpServer.Shutdown(ctx); err != nil {
+			return fmt.Errorf("error occurred during server shutdown: %w", err)
+		}
+	}
+	if b.metricsServer != nil {
+		if err := b.metricsServer.Shutdown(ctx); err != nil {
+			return fmt.Errorf("error occurred during metrics server shutdown: %w", err)
+		}
+	}
+
+	if b.stopPolling != nil {
+		close(b.stopPolling)
+		select {
+		case <-b.pollingDone:
+		case <-ctx.Done():
+			return fmt.Errorf("error occurred during polling stop: %w", ctx.Err())
+		}
+	}
+
+	if b.stopWorkers != nil {
+		b.stopWorkers()
+	}
+
+	if err := b.inFlight.wait(ctx); err != nil {
+		return err
+	}
+
+	workersDone := make(chan struct{})
+	go func() {
+		b.workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("outbox worker is still running: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStop_WaitsForRunningHandlers(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	started, release, finished := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	bot.inFlight.run(func() {
+		close(started)
+		<-release
+		close(finished)
+	})
+	<-started
+
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+	err := bot.Stop(context.Background())
+
+	assert.NoError(t, err)
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Stop returned before the handler finished")
+	}
+}
+
+func TestStop_WhenHandlerOutlivesDeadline(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	bot.inFlight.run(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := bot.Stop(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualError(t, err, "1 update handlers are still running: context deadline exceeded")
+}
+
+func TestStop_StopsOutboxWorker(t *testing.T) {
+	bot := newTestBot(nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	bot.stopWorkers = cancel
+	bot.workers.Add(1)
+	go func() {
+		defer bot.workers.Done()
+		bot.runOutboxWorker(ctx)
+	}()
+
+	err := bot.Stop(context.Background())
+
+	assert.NoError(t, err)
+	assert.Error(t, ctx.Err())
+}
+
+func TestInFlight_WhenIdle(t *testing.T) {
+	var f inFlight
+	done := make(chan struct{})
+	f.run(func() { close(done) })
+	<-done
+
+	err := f.wait(context.Background())
+
+	assert.NoError(t, err)
+}
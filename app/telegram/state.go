@@ -1,21 +1,79 @@
 package telegram
 
-import "github.com/deordie/deordie-bot/app/storage"
+import (
+	"time"
+
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/storage"
+)
+
+const (
+	stepUrl         = "url"
+	stepArticle     = "article"
+	stepDescription = "description"
+	stepLevel       = "level"
+	stepTopics      = "topics"
+	stepPreview     = "preview"
+)
+
+var steps = []string{stepUrl, stepArticle, stepDescription, stepLevel, stepTopics, stepPreview}
 
 type UserArticleState struct {
 	UserId      int64
+	Step        string
 	Url         string
 	Description string
 	Level       string
 	Topics      []string
+	Article     *extractor.Article
+}
+
+func (s *UserArticleState) isComplete() bool {
+	return s.Url != "" && s.Description != "" && s.Level != "" && len(s.Topics) > 0
+}
+
+func (s *UserArticleState) nextStep() string {
+	switch {
+	case s.Url == "":
+		return stepUrl
+	case s.Description == "":
+		return stepDescription
+	case s.Level == "":
+		return stepLevel
+	case len(s.Topics) == 0:
+		return stepTopics
+	}
+	return stepPreview
+}
+
+func (s *UserArticleState) previousStep() (string, bool) {
+	for i, step := range steps {
+		if step == s.Step && i > 0 {
+			return steps[i-1], true
+		}
+	}
+	return "", false
+}
+
+func (s *UserArticleState) currentStep() string {
+	if s.Step == "" {
+		return s.nextStep()
+	}
+	return s.Step
 }
 
 type StateStorage struct {
-	storage.InMemoryStorage[UserArticleState]
+	storage.Storage[UserArticleState]
+	ttl time.Duration
 }
 
-func NewStateStorage() *StateStorage {
+func NewStateStorage(s storage.Storage[UserArticleState], ttl time.Duration) *StateStorage {
 	return &StateStorage{
-		InMemoryStorage: *storage.NewInMemoryStorage[UserArticleState](),
+		Storage: s,
+		ttl:     ttl,
 	}
 }
+
+func (s *StateStorage) Set(key int64, value UserArticleState) {
+	s.Storage.SetWithTTL(key, value, s.ttl)
+}
@@ -0,0 +1,334 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/github"
+	tele "gopkg.in/telebot.v3"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	topicButton      = "topic"
+	topicsPageButton = "topics_page"
+	topicsDoneButton = "topics_done"
+
+	topicsPerPage        = 8
+	maxTopicSuggestions  = 3
+	labelCacheTtl        = 10 * time.Minute
+	labelCacheRetryDelay = 30 * time.Second
+)
+
+// labelCache keeps the repo labels for a while, so the topic picker doesn't call GitHub on every click.
+type labelCache struct {
+	lister    githubLabelLister
+	ttl       time.Duration
+	mutex     sync.Mutex
+	labels    []github.Label
+	err       error
+	expiresAt time.Time
+	refreshed chan struct{}
+}
+
+func newLabelCache(lister githubLabelLister, ttl time.Duration) *labelCache {
+	return &labelCache{
+		lister: lister,
+		ttl:    ttl,
+	}
+}
+
+func (c *labelCache) get(ctx context.Context) ([]github.Label, error) {
+	c.mutex.Lock()
+	if time.Now().Before(c.expiresAt) {
+		defer c.mutex.Unlock()
+		if c.labels == nil {
+			return nil, c.err
+		}
+		return c.labels, nil
+	}
+	if refreshed := c.refreshed; refreshed != nil {
+		labels := c.labels
+		c.mutex.Unlock()
+		if labels != nil {
+			return labels, nil
+		}
+		select {
+		case <-refreshed:
+			return c.get(ctx)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c.refreshed = make(chan struct{})
+	c.mutex.Unlock()
+
+	labels, err := c.lister.ListLabels(ctx)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	close(c.refreshed)
+	c.refreshed = nil
+	if err != nil {
+		// Backs off, so a GitHub outage doesn't cost a listing on every click.
+		c.err = err
+		c.expiresAt = time.Now().Add(labelCacheRetryDelay)
+		if c.labels != nil {
+			slog.WarnContext(ctx, "Failed to refresh GitHub labels, using the cached ones", "error", err)
+			return c.labels, nil
+		}
+		return nil, err
+	}
+
+	c.labels = labels
+	c.err = nil
+	c.expiresAt = time.Now().Add(c.ttl)
+	return labels, nil
+}
+
+func (b *Bot) topicLabels(ctx context.Context) ([]github.Label, error) {
+	if len(b.topics) == 0 {
+		return b.labelCache.get(ctx)
+	}
+
+	labels := make([]github.Label, 0, len(b.topics))
+	for _, topic := range b.topics {
+		labels = append(labels, github.Label{Name: github.TopicLabelPrefix + topic})
+	}
+	return labels, nil
+}
+
+func (b *Bot) sendTopicPicker(ctx tele.Context, state *UserArticleState) error {
+	m := b.messages(ctx)
+	return ctx.Send(b.formatTopicsPrompt(m, state), b.getTopicsKeyboard(requestContext(ctx), m, state, 0))
+}
+
+func (b *Bot) updateTopicPicker(ctx tele.Context, state *UserArticleState, page int) error {
+	m := b.messages(ctx)
+	return ctx.Edit(b.formatTopicsPrompt(m, state), b.getTopicsKeyboard(requestContext(ctx), m, state, page))
+}
+
+func (b *Bot) handleTopicToggle(ctx tele.Context) error {
+	_ = ctx.Respond()
+
+	userId := ctx.Sender().ID
+	state, ok := b.stateStorage.Get(userId)
+	if !ok || state.currentStep() != stepTopics {
+		return nil
+	}
+
+	pageValue, key, _ := strings.Cut(ctx.Data(), "|")
+	page, _ := strconv.Atoi(pageValue)
+	callCtx, cancel := b.newCallContext(requestContext(ctx))
+	defer cancel()
+	topic, ok := findTopic(b.pickerTopics(callCtx, &state), key)
+	if !ok {
+		return nil
+	}
+
+	state.Step = stepTopics
+	state.Topics = toggleTopic(state.Topics, topic)
+	b.stateStorage.Set(userId, state)
+	return b.updateTopicPicker(ctx, &state, page)
+}
+
+func (b *Bot) handleTopicsPage(ctx tele.Context) error {
+	_ = ctx.Respond()
+
+	state, ok := b.stateStorage.Get(ctx.Sender().ID)
+	if !ok || state.currentStep() != stepTopics {
+		return nil
+	}
+
+	page, err := strconv.Atoi(ctx.Data())
+	if err != nil {
+		return nil
+	}
+	return b.updateTopicPicker(ctx, &state, page)
+}
+
+func (b *Bot) handleTopicsDone(ctx tele.Context) error {
+	m := b.messages(ctx)
+	userId := ctx.Sender().ID
+	state, ok := b.stateStorage.Get(userId)
+	if !ok || state.currentStep() != stepTopics {
+		return ctx.Respond()
+	}
+
+	if len(state.Topics) == 0 {
+		return ctx.Respond(&tele.CallbackResponse{Text: m.NoTopicsChosen})
+	}
+
+	_ = ctx.Respond()
+	state.Step = state.nextStep()
+	b.stateStorage.Set(userId, state)
+	return b.promptStep(ctx, &state)
+}
+
+func (b *Bot) matchTopics(ctx context.Context, m *Messages, typed []string) ([]string, string) {
+	callCtx, cancel := b.newCallContext(ctx)
+	defer cancel()
+
+	labels, err := b.topicLabels(callCtx)
+	if err != nil {
+		slog.WarnContext(callCtx, "Failed to list GitHub labels", "error", err)
+	}
+
+	restricted := len(b.topics) > 0 || (!b.createLabels && err == nil)
+	topics := make([]string, 0, len(typed))
+	var suggestions []string
+	for _, topic := range typed {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+
+		match, ok := github.MatchLabel(labels, github.TopicLabelPrefix, topic)
+		closest := github.ClosestLabels(labels, github.TopicLabelPrefix, topic, maxTopicSuggestions)
+		switch {
+		case ok:
+			topic = match
+		case restricted && len(closest) > 0:
+			suggestions = append(suggestions, fmt.Sprintf(m.TopicNotAllowedSuggestion, topic, strings.Join(closest, ", ")))
+			continue
+		case restricted:
+			suggestions = append(suggestions, fmt.Sprintf(m.TopicNotAllowed, topic))
+			continue
+		case len(closest) > 0:
+			suggestions = append(suggestions, fmt.Sprintf(m.NewTopic, topic, strings.Join(closest, ", ")))
+		}
+		topics = addTopic(topics, topic)
+	}
+
+	if len(suggestions) == 0 {
+		return topics, ""
+	}
+	return topics, strings.Join(suggestions, "\n") + fmt.Sprintf(m.EditTopicsHint, editCommand)
+}
+
+func (b *Bot) getTopicsKeyboard(ctx context.Context, m *Messages, state *UserArticleState, page int) *tele.ReplyMarkup {
+	callCtx, cancel := b.newCallContext(ctx)
+	defer cancel()
+
+	topics := b.pickerTopics(callCtx, state)
+	pages := max(1, (len(topics)+topicsPerPage-1)/topicsPerPage)
+	page = min(max(page, 0), pages-1)
+
+	keyboard := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	var row tele.Row
+	for _, topic := range topics[min(page*topicsPerPage, len(topics)):min((page+1)*topicsPerPage, len(topics))] {
+		data := strconv.Itoa(page) + "|" + topicKey(topic)
+		text := topic
+		if containsTopic(state.Topics, topic) {
+			text = "✅ " + topic
+		}
+		row = append(row, keyboard.Data(text, topicButton, data))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	if pages > 1 {
+		var navigation tele.Row
+		if page > 0 {
+			navigation = append(navigation, keyboard.Data(m.PreviousButton, topicsPageButton, strconv.Itoa(page-1)))
+		}
+		if page < pages-1 {
+			navigation = append(navigation, keyboard.Data(m.NextButton, topicsPageButton, strconv.Itoa(page+1)))
+		}
+		rows = append(rows, navigation)
+	}
+
+	rows = append(rows, keyboard.Row(keyboard.Data(m.DoneButton, topicsDoneButton)))
+	keyboard.Inline(rows...)
+	return keyboard
+}
+
+func (b *Bot) pickerTopics(ctx context.Context, state *UserArticleState) []string {
+	labels, err := b.topicLabels(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to list GitHub topic labels", "error", err)
+	}
+
+	var topics []string
+	for _, label := range labels {
+		if len(label.Name) > len(github.TopicLabelPrefix) && strings.EqualFold(label.Name[:len(github.TopicLabelPrefix)], github.TopicLabelPrefix) {
+			topics = append(topics, label.Name[len(github.TopicLabelPrefix):])
+		}
+	}
+	for _, topic := range state.Topics {
+		if !containsTopic(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	sortTopics(topics)
+	return topics
+}
+
+// topicKey identifies the topic in the callback data, which is too short for the long topics themselves.
+func topicKey(topic string) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(strings.ToLower(topic)))
+	return strconv.FormatUint(hash.Sum64(), 36)
+}
+
+func (b *Bot) formatTopicsPrompt(m *Messages, state *UserArticleState) string {
+	prompt := fmt.Sprintf(m.TopicsPrompt, m.CancelKeyword)
+	if len(b.topics) > 0 || !b.createLabels {
+		prompt = fmt.Sprintf(m.AllowedTopicsPrompt, m.CancelKeyword)
+	}
+	if len(state.Topics) > 0 {
+		prompt += fmt.Sprintf(m.SelectedTopics, strings.Join(state.Topics, ", "))
+	}
+	return prompt
+}
+
+func toggleTopic(topics []string, topic string) []string {
+	for i, selected := range topics {
+		if strings.EqualFold(selected, topic) {
+			return append(topics[:i:i], topics[i+1:]...)
+		}
+	}
+	return append(topics, topic)
+}
+
+func findTopic(topics []string, key string) (string, bool) {
+	for _, topic := range topics {
+		if topicKey(topic) == key {
+			return topic, true
+		}
+	}
+	return "", false
+}
+
+func addTopic(topics []string, topic string) []string {
+	if containsTopic(topics, topic) {
+		return topics
+	}
+	return append(topics, topic)
+}
+
+func containsTopic(topics []string, topic string) bool {
+	for _, t := range topics {
+		if strings.EqualFold(t, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortTopics(topics []string) {
+	sort.SliceStable(topics, func(i, j int) bool {
+		return strings.ToLower(topics[i]) < strings.ToLower(topics[j])
+	})
+}
@@ -0,0 +1,256 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/deordie/deordie-bot/app/extractor"
+	"github.com/deordie/deordie-bot/app/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingLabelLister lists the labels once released.
+type blockingLabelLister struct {
+	started chan struct{}
+	release chan struct{}
+	labels  []github.Label
+}
+
+func (l *blockingLabelLister) ListLabels(_ context.Context) ([]github.Label, error) {
+	close(l.started)
+	<-l.release
+	return l.labels, nil
+}
+
+func newTopicLabels(count int) []github.Label {
+	labels := []github.Label{{Name: "level:advanced"}}
+	for i := 0; i < count; i++ {
+		labels = append(labels, github.Label{Name: fmt.Sprintf("topic:topic%02d", i)})
+	}
+	return labels
+}
+
+func TestTopicToggleHandler(t *testing.T) {
+	userId := int64(3000)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return(newTopicLabels(3), nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Data").Return("0|" + topicKey("topic01"))
+	mockContext.On("Edit", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepTopics, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"topic00"}})
+
+	_ = bot.handleTopicToggle(mockContext)
+	selected, _ := bot.stateStorage.Get(userId)
+	_ = bot.handleTopicToggle(mockContext)
+	unselected, _ := bot.stateStorage.Get(userId)
+
+	assert.Equal(t, []string{"topic00", "topic01"}, selected.Topics)
+	assert.Equal(t, []string{"topic00"}, unselected.Topics)
+	mockContext.AssertCalled(t, "Edit", "Step 4. Choose topics below or type new ones as a comma separated list, then press \"Done\". To abort the operation type \"cancel\".\nSelected: topic00, topic01", mock.Anything)
+	mockGitHub.AssertNumberOfCalls(t, "ListLabels", 1)
+}
+
+func TestTopicToggleHandler_WhenTopicIsLong(t *testing.T) {
+	userId := int64(3005)
+	topic := strings.Repeat("distributed-systems-", 5)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:" + topic}}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Edit", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	state := UserArticleState{UserId: userId, Step: stepTopics, Url: "https://example.com", Description: "Nice article.", Level: "advanced"}
+	bot.stateStorage.Set(userId, state)
+
+	button := bot.getTopicsKeyboard(context.Background(), DefaultMessages(), &state, 0).InlineKeyboard[0][0]
+	mockContext.On("Data").Return(button.Data)
+	_ = bot.handleTopicToggle(mockContext)
+
+	selected, _ := bot.stateStorage.Get(userId)
+	assert.Equal(t, topic, button.Text)
+	assert.LessOrEqual(t, len(topicButton)+len(button.Data)+2, 64, "expected the callback data within the Telegram limit")
+	assert.Equal(t, []string{topic}, selected.Topics)
+}
+
+func TestLabelCache_DoesNotBlockWhileRefreshing(t *testing.T) {
+	release := make(chan struct{})
+	lister := &blockingLabelLister{started: make(chan struct{}), release: release, labels: newTopicLabels(1)}
+	cache := newLabelCache(lister, time.Nanosecond)
+	cache.labels = newTopicLabels(2)
+
+	refreshed := make(chan []github.Label)
+	go func() {
+		labels, _ := cache.get(context.Background())
+		refreshed <- labels
+	}()
+	<-lister.started
+	stale, err := cache.get(context.Background())
+	close(release)
+
+	assert.Nil(t, err, "unexpected error")
+	assert.Len(t, stale, 3, "expected the stale labels while the refresh runs")
+	assert.Len(t, <-refreshed, 2)
+}
+
+func TestLabelCache_BacksOffAfterFailure(t *testing.T) {
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label(nil), fmt.Errorf("list labels error"))
+	cache := newLabelCache(mockGitHub, time.Nanosecond)
+	cache.labels = newTopicLabels(2)
+
+	first, firstErr := cache.get(context.Background())
+	second, secondErr := cache.get(context.Background())
+
+	assert.Nil(t, firstErr, "unexpected error")
+	assert.Nil(t, secondErr, "unexpected error")
+	assert.Len(t, first, 3, "expected the last good labels")
+	assert.Len(t, second, 3, "expected the last good labels")
+	mockGitHub.AssertNumberOfCalls(t, "ListLabels", 1)
+}
+
+func TestLabelCache_BacksOffAfterFailureWithoutLabels(t *testing.T) {
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label(nil), fmt.Errorf("list labels error"))
+	cache := newLabelCache(mockGitHub, time.Minute)
+
+	_, firstErr := cache.get(context.Background())
+	_, secondErr := cache.get(context.Background())
+
+	assert.EqualError(t, firstErr, "list labels error")
+	assert.EqualError(t, secondErr, "list labels error")
+	mockGitHub.AssertNumberOfCalls(t, "ListLabels", 1)
+}
+
+func TestTopicsDoneHandler_WhenNoTopics(t *testing.T) {
+	userId := int64(3001)
+	bot := newTestBot(nil, nil)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepTopics, Url: "https://example.com", Description: "Nice article.", Level: "advanced"})
+
+	_ = bot.handleTopicsDone(mockContext)
+
+	state, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, stepTopics, state.Step)
+	mockContext.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestGetTopicsKeyboard(t *testing.T) {
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return(newTopicLabels(10), nil)
+	bot := newTestBot(nil, mockGitHub)
+	state := &UserArticleState{Topics: []string{"topic09", "new-topic"}}
+
+	firstPage := bot.getTopicsKeyboard(context.Background(), DefaultMessages(), state, 0).InlineKeyboard
+	lastPage := bot.getTopicsKeyboard(context.Background(), DefaultMessages(), state, 1).InlineKeyboard
+
+	assert.Len(t, firstPage, 6, "expected 4 rows of topics, navigation and done")
+	assert.Equal(t, "✅ new-topic", firstPage[0][0].Text)
+	assert.Equal(t, "0|"+topicKey("new-topic"), firstPage[0][0].Data)
+	assert.Equal(t, "topic00", firstPage[0][1].Text)
+	assert.Equal(t, "Next »", firstPage[4][0].Text)
+	assert.Equal(t, "Done", firstPage[5][0].Text)
+
+	assert.Len(t, lastPage, 4, "expected 2 rows of topics, navigation and done")
+	assert.Equal(t, "topic07", lastPage[0][0].Text)
+	assert.Equal(t, "✅ topic09", lastPage[1][0].Text)
+	assert.Equal(t, "« Previous", lastPage[2][0].Text)
+}
+
+func TestOnTextHandler_WhenTopicsRestricted(t *testing.T) {
+	userId := int64(3002)
+	mockGitHub := new(MockGitHubClient)
+	bot := newTestBot(nil, mockGitHub)
+	bot.topics = []string{"kafka", "streaming"}
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("Kafka, straming, spark")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepTopics, Url: "https://example.com", Description: "Nice article.", Level: "advanced"})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"kafka"}, actualState.Topics)
+	mockContext.AssertCalled(t, "Send", "\"straming\" is not one of the allowed topics, did you mean: streaming?\n\"spark\" is not one of the allowed topics, please choose them with the buttons.\nUse /edit topics to change them.", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "Step 4. Choose topics below, then press \"Done\". To abort the operation type \"cancel\".\nSelected: kafka", mock.Anything)
+	mockGitHub.AssertNotCalled(t, "ListLabels")
+}
+
+func TestOnTextHandler_WhenLabelsNotCreated(t *testing.T) {
+	userId := int64(3003)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:Streaming"}, {Name: "topic:kafka"}}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	bot.createLabels = false
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Text").Return("Kafka, straming, spark")
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId})
+	bot.stateStorage.Set(userId, UserArticleState{UserId: userId, Step: stepTopics, Url: "https://example.com", Description: "Nice article.", Level: "advanced"})
+
+	_ = bot.handleOnText(mockContext)
+
+	actualState, ok := bot.stateStorage.Get(userId)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"kafka"}, actualState.Topics)
+	mockContext.AssertCalled(t, "Send", "\"straming\" is not one of the allowed topics, did you mean: Streaming?\n\"spark\" is not one of the allowed topics, please choose them with the buttons.\nUse /edit topics to change them.", mock.Anything)
+	mockContext.AssertCalled(t, "Send", "Step 4. Choose topics below, then press \"Done\". To abort the operation type \"cancel\".\nSelected: kafka", mock.Anything)
+}
+
+func TestSendPreview_WhenLabelsNotCreated(t *testing.T) {
+	userId := int64(3004)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:kafka"}, {Name: "level:advanced"}}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	bot.createLabels = false
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	state := UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"kafka", "spark"}, Article: &extractor.Article{Title: "Article Title", Url: "https://example.com"}}
+
+	_ = bot.sendPreview(mockContext, &state)
+
+	mockContext.AssertCalled(t, "Send", mock.MatchedBy(func(preview string) bool {
+		return strings.Contains(preview, "Labels: level:advanced, topic:kafka\n")
+	}), mock.Anything)
+}
+
+func TestSendPreview_WhenLabelsCreated(t *testing.T) {
+	userId := int64(3006)
+	mockGitHub := new(MockGitHubClient)
+	mockGitHub.On("ListLabels").Return([]github.Label{{Name: "topic:Kafka"}, {Name: "level:advanced"}}, nil)
+	bot := newTestBot(nil, mockGitHub)
+	mockContext := new(MockTelegramBotContext)
+	mockContext.On("Send", mock.Anything, mock.Anything).Return(nil)
+	mockContext.On("Sender").Return(&tele.User{ID: userId, Username: "nickname"})
+	state := UserArticleState{UserId: userId, Url: "https://example.com", Description: "Nice article.", Level: "advanced", Topics: []string{"kafka", "spark"}, Article: &extractor.Article{Title: "Article Title", Url: "https://example.com"}}
+
+	_ = bot.sendPreview(mockContext, &state)
+
+	mockContext.AssertCalled(t, "Send", mock.MatchedBy(func(preview string) bool {
+		return strings.Contains(preview, "Labels: level:advanced, topic:Kafka, topic:spark\n")
+	}), mock.Anything)
+}
+
+func TestGetTopicsKeyboard_WhenTopicsRestricted(t *testing.T) {
+	mockGitHub := new(MockGitHubClient)
+	bot := newTestBot(nil, mockGitHub)
+	bot.topics = []string{"streaming", "kafka"}
+
+	rows := bot.getTopicsKeyboard(context.Background(), &Messages{DoneButton: "Ready"}, &UserArticleState{}, 0).InlineKeyboard
+
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "kafka", rows[0][0].Text)
+	assert.Equal(t, "streaming", rows[0][1].Text)
+	assert.Equal(t, "Ready", rows[1][0].Text)
+	mockGitHub.AssertNotCalled(t, "ListLabels")
+}